@@ -3,57 +3,96 @@ package examples_test
 import (
 	"context"
 	"database/sql"
+	"strings"
 	"testing"
 
 	_ "github.com/lib/pq"
 	"github.com/stretchr/testify/require"
 	"github.com/testcontainers/testcontainers-go"
 	"github.com/testcontainers/testcontainers-go/modules/postgres"
+
+	"github.com/testcontainers/testcontainers-go/examples/internal/images"
+	"github.com/testcontainers/testcontainers-go/examples/internal/matrix"
 )
 
-// TestBasicPostgres demonstrates the most basic usage of the PostgreSQL module
+// TestBasicPostgres demonstrates the most basic usage of the PostgreSQL module,
+// against every supported version
 func TestBasicPostgres(t *testing.T) {
-	ctx := context.Background()
-
-	// Start PostgreSQL container with default settings
-	pgContainer, err := postgres.Run(ctx, "postgres:16-alpine", postgres.BasicWaitStrategies())
-	testcontainers.CleanupContainer(t, pgContainer)
-	require.NoError(t, err)
-
-	// Get connection string
-	connStr, err := pgContainer.ConnectionString(ctx, "sslmode=disable")
-	require.NoError(t, err)
-
-	// Connect to database
-	db, err := sql.Open("postgres", connStr)
-	require.NoError(t, err)
-	defer db.Close()
+	t.Parallel()
 
-	// Verify connection
-	err = db.Ping()
-	require.NoError(t, err)
+	ctx := context.Background()
 
-	// Run a simple query
-	var result int
-	err = db.QueryRow("SELECT 1 + 1").Scan(&result)
-	require.NoError(t, err)
-	require.Equal(t, 2, result)
+	// One subtest per image, at most two containers at a time
+	matrix.Of(images.PostgresVersions...).Run(t, func(t *testing.T, image images.Image) {
+		// Start PostgreSQL container with default settings
+		pgContainer, err := postgres.Run(ctx, image.Ref(), postgres.BasicWaitStrategies(), recordStartup(t))
+		testcontainers.CleanupContainer(t, pgContainer)
+		require.NoError(t, err)
+
+		// Get connection string
+		connStr, err := pgContainer.ConnectionString(ctx, "sslmode=disable")
+		require.NoError(t, err)
+
+		// Connect to database
+		db, err := sql.Open("postgres", connStr)
+		require.NoError(t, err)
+		defer db.Close()
+
+		// Verify connection
+		err = db.Ping()
+		require.NoError(t, err)
+
+		// Run a simple query
+		var result int
+		err = db.QueryRow("SELECT 1 + 1").Scan(&result)
+		require.NoError(t, err)
+		require.Equal(t, 2, result)
+
+		// The server reports the version of the image, proving which one ran
+		var version string
+		err = db.QueryRow("SHOW server_version").Scan(&version)
+		require.NoError(t, err)
+		if major := majorVersion(image.Ref()); major != "" {
+			require.True(t, strings.HasPrefix(version, major+"."), "%s reports PostgreSQL %s", image, version)
+		}
+
+		t.Logf("Successfully connected to PostgreSQL %s and ran a query", version)
+	})
+}
 
-	t.Log("Successfully connected to PostgreSQL and ran a query")
+// majorVersion returns the leading number of the tag of ref, such as "13"
+// for postgres:13-alpine, or "" if the tag does not start with one.
+func majorVersion(ref string) string {
+	if strings.Contains(ref, "@") {
+		return ""
+	}
+	i := strings.LastIndexByte(ref, ':')
+	if i < strings.LastIndexByte(ref, '/') {
+		return ""
+	}
+	tag := ref[i+1:]
+	end := strings.IndexFunc(tag, func(r rune) bool { return r < '0' || r > '9' })
+	if end < 0 {
+		end = len(tag)
+	}
+	return tag[:end]
 }
 
 // TestPostgresWithCustomConfig demonstrates using custom database, user, and password
 func TestPostgresWithCustomConfig(t *testing.T) {
+	t.Parallel()
+
 	ctx := context.Background()
 
 	// Start PostgreSQL with custom configuration
 	pgContainer, err := postgres.Run(
 		ctx,
-		"postgres:16-alpine",
+		images.Postgres.Ref(),
 		postgres.WithDatabase("testdb"),
 		postgres.WithUsername("testuser"),
 		postgres.WithPassword("testpass"),
 		postgres.BasicWaitStrategies(),
+		recordStartup(t),
 	)
 	testcontainers.CleanupContainer(t, pgContainer)
 	require.NoError(t, err)
@@ -80,15 +119,18 @@ func TestPostgresWithCustomConfig(t *testing.T) {
 
 // TestPostgresWithSchema demonstrates using init scripts to set up a schema
 func TestPostgresWithSchema(t *testing.T) {
+	t.Parallel()
+
 	ctx := context.Background()
 
-	// Note: In a real test, you would create a schema.sql file in testdata/
-	// For this example, we'll use WithDatabase and create the table manually
+	// Note: Real projects bootstrap the schema with init scripts or migrations,
+	// see 11_postgres_init_scripts_test.go. Here the table is created manually
 	pgContainer, err := postgres.Run(
 		ctx,
-		"postgres:16-alpine",
+		images.Postgres.Ref(),
 		postgres.WithDatabase("appdb"),
 		postgres.BasicWaitStrategies(),
+		recordStartup(t),
 	)
 	testcontainers.CleanupContainer(t, pgContainer)
 	require.NoError(t, err)
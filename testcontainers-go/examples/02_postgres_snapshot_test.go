@@ -7,57 +7,44 @@ import (
 
 	_ "github.com/lib/pq"
 	"github.com/stretchr/testify/require"
-	"github.com/testcontainers/testcontainers-go"
 	"github.com/testcontainers/testcontainers-go/modules/postgres"
 )
 
+// snapshotSeed is the initial schema and data of TestPostgresSnapshot
+const snapshotSeed = `
+	CREATE TABLE products (
+		id SERIAL PRIMARY KEY,
+		name TEXT NOT NULL,
+		price DECIMAL(10, 2) NOT NULL
+	);
+	INSERT INTO products (name, price) VALUES ('Widget', 9.99);
+`
+
+// countersSeed is the schema of TestPostgresMultipleSnapshots
+const countersSeed = `CREATE TABLE counters (id INT PRIMARY KEY, value INT)`
+
 // TestPostgresSnapshot demonstrates using snapshots for test isolation
 // This is useful when you want to run multiple tests against the same initial state
 func TestPostgresSnapshot(t *testing.T) {
+	t.Parallel()
+
 	ctx := context.Background()
 
 	// Start PostgreSQL container with a custom database (required for snapshots)
-	// Note: Cannot snapshot the default 'postgres' system database
-	pgContainer, err := postgres.Run(
-		ctx,
-		"postgres:16-alpine",
-		postgres.WithDatabase("snapshotdb"),
-		postgres.BasicWaitStrategies(),
-	)
-	testcontainers.CleanupContainer(t, pgContainer)
-	require.NoError(t, err)
-
-	connStr, err := pgContainer.ConnectionString(ctx, "sslmode=disable")
-	require.NoError(t, err)
-
-	db, err := sql.Open("postgres", connStr)
-	require.NoError(t, err)
-	defer db.Close()
-
-	// Create initial schema and data
-	_, err = db.Exec(`
-		CREATE TABLE products (
-			id SERIAL PRIMARY KEY,
-			name TEXT NOT NULL,
-			price DECIMAL(10, 2) NOT NULL
-		)
-	`)
-	require.NoError(t, err)
-
-	_, err = db.Exec(`INSERT INTO products (name, price) VALUES ($1, $2)`, "Widget", 9.99)
-	require.NoError(t, err)
-
-	// Close connection before snapshot (PostgreSQL can't snapshot a database with active connections)
-	db.Close()
+	// and the initial schema and data. Note: Cannot snapshot the default
+	// 'postgres' system database. With EXAMPLES_REUSE set, re-running the
+	// test picks up the container of the previous run, restored to the
+	// seed, see 16_container_reuse_test.go
+	pgContainer, connStr := reusablePostgres(t, snapshotSeed)
 
 	// Take a snapshot of the initial state
-	err = pgContainer.Snapshot(ctx, postgres.WithSnapshotName("initial_state"))
+	err := pgContainer.Snapshot(ctx, postgres.WithSnapshotName("initial_state"))
 	require.NoError(t, err)
 
 	t.Log("Snapshot created with initial state")
 
-	// Reconnect to modify the database
-	db, err = sql.Open("postgres", connStr)
+	// Connect to modify the database
+	db, err := sql.Open("postgres", connStr)
 	require.NoError(t, err)
 
 	// Modify the database
@@ -104,38 +91,22 @@ func TestPostgresSnapshot(t *testing.T) {
 
 // TestPostgresMultipleSnapshots demonstrates using multiple named snapshots
 func TestPostgresMultipleSnapshots(t *testing.T) {
-	ctx := context.Background()
-
-	// Use a custom database name (not 'postgres') for snapshots to work properly
-	pgContainer, err := postgres.Run(
-		ctx,
-		"postgres:16-alpine",
-		postgres.WithDatabase("testdb"),
-		postgres.BasicWaitStrategies(),
-	)
-	testcontainers.CleanupContainer(t, pgContainer)
-	require.NoError(t, err)
-
-	connStr, err := pgContainer.ConnectionString(ctx, "sslmode=disable")
-	require.NoError(t, err)
+	t.Parallel()
 
-	db, err := sql.Open("postgres", connStr)
-	require.NoError(t, err)
-	defer db.Close()
+	ctx := context.Background()
 
-	// Create table
-	_, err = db.Exec(`CREATE TABLE counters (id INT PRIMARY KEY, value INT)`)
-	require.NoError(t, err)
+	// Start PostgreSQL with the counters table, reused between runs with
+	// EXAMPLES_REUSE set. A previous run leaves its snapshots behind: taking
+	// a snapshot again replaces it
+	pgContainer, connStr := reusablePostgres(t, countersSeed)
 
 	// State 1: Empty table
-	// Close connection before snapshot (PostgreSQL can't snapshot a database with active connections)
-	db.Close()
-	err = pgContainer.Snapshot(ctx, postgres.WithSnapshotName("empty"))
+	err := pgContainer.Snapshot(ctx, postgres.WithSnapshotName("empty"))
 	require.NoError(t, err)
 	t.Log("Snapshot 'empty' created")
 
-	// Reconnect to make changes
-	db, err = sql.Open("postgres", connStr)
+	// Connect to make changes
+	db, err := sql.Open("postgres", connStr)
 	require.NoError(t, err)
 
 	// State 2: One record
@@ -2,51 +2,80 @@ package examples_test
 
 import (
 	"context"
+	"fmt"
+	"io"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/redis/go-redis/v9"
 	"github.com/stretchr/testify/require"
 	"github.com/testcontainers/testcontainers-go"
+	"github.com/testcontainers/testcontainers-go/exec"
 	tcredis "github.com/testcontainers/testcontainers-go/modules/redis"
+
+	"github.com/testcontainers/testcontainers-go/examples/internal/images"
+	"github.com/testcontainers/testcontainers-go/examples/internal/matrix"
+	"github.com/testcontainers/testcontainers-go/examples/waitx"
 )
 
-// TestBasicRedis demonstrates basic Redis operations
+// TestBasicRedis demonstrates basic Redis operations, against every
+// supported server
 func TestBasicRedis(t *testing.T) {
-	ctx := context.Background()
-
-	// Start Redis container
-	redisContainer, err := tcredis.Run(ctx, "redis:7-alpine")
-	testcontainers.CleanupContainer(t, redisContainer)
-	require.NoError(t, err)
-
-	// Get connection string
-	connStr, err := redisContainer.ConnectionString(ctx)
-	require.NoError(t, err)
-
-	// Connect to Redis
-	opt, err := redis.ParseURL(connStr)
-	require.NoError(t, err)
-
-	client := redis.NewClient(opt)
-	defer client.Close()
-
-	// Test SET and GET
-	err = client.Set(ctx, "greeting", "Hello, Testcontainers!", 0).Err()
-	require.NoError(t, err)
+	t.Parallel()
 
-	val, err := client.Get(ctx, "greeting").Result()
-	require.NoError(t, err)
-	require.Equal(t, "Hello, Testcontainers!", val)
+	ctx := context.Background()
 
-	t.Log("Successfully performed SET and GET operations")
+	// One subtest per image, at most two containers at a time
+	matrix.Of(images.RedisVersions...).Run(t, func(t *testing.T, image images.Image) {
+		// Start Redis container
+		redisContainer, err := tcredis.Run(ctx, image.Ref(), recordStartup(t))
+		testcontainers.CleanupContainer(t, redisContainer)
+		require.NoError(t, err)
+
+		// Get connection string
+		connStr, err := redisContainer.ConnectionString(ctx)
+		require.NoError(t, err)
+
+		// Connect to Redis
+		opt, err := redis.ParseURL(connStr)
+		require.NoError(t, err)
+
+		client := redis.NewClient(opt)
+		defer client.Close()
+
+		// Test SET and GET
+		err = client.Set(ctx, "greeting", "Hello, Testcontainers!", 0).Err()
+		require.NoError(t, err)
+
+		val, err := client.Get(ctx, "greeting").Result()
+		require.NoError(t, err)
+		require.Equal(t, "Hello, Testcontainers!", val)
+
+		// The server reports the version of the image, proving which one
+		// ran. Valkey keeps redis_version for compatibility and reports
+		// its own in valkey_version.
+		info, err := client.InfoMap(ctx, "server").Result()
+		require.NoError(t, err)
+		version := info["Server"]["valkey_version"]
+		if version == "" {
+			version = info["Server"]["redis_version"]
+		}
+		if major := majorVersion(image.Ref()); major != "" {
+			require.True(t, strings.HasPrefix(version, major+"."), "%s reports version %q", image, version)
+		}
+
+		t.Logf("Successfully performed SET and GET operations on %s", version)
+	})
 }
 
 // TestRedisWithExpiration demonstrates key expiration
 func TestRedisWithExpiration(t *testing.T) {
+	t.Parallel()
+
 	ctx := context.Background()
 
-	redisContainer, err := tcredis.Run(ctx, "redis:7-alpine")
+	redisContainer, err := tcredis.Run(ctx, images.Redis.Ref(), recordStartup(t))
 	testcontainers.CleanupContainer(t, redisContainer)
 	require.NoError(t, err)
 
@@ -70,21 +99,24 @@ func TestRedisWithExpiration(t *testing.T) {
 
 	t.Log("Key set with 2-second expiration")
 
-	// Wait for expiration
-	time.Sleep(3 * time.Second)
+	// Poll until the key is gone instead of sleeping past the expiration
+	require.Eventually(t, func() bool {
+		return client.Exists(ctx, "temporary").Val() == 0
+	}, 5*time.Second, 100*time.Millisecond, "Key should have expired")
 
-	// Verify key is gone
 	_, err = client.Get(ctx, "temporary").Result()
-	require.Equal(t, redis.Nil, err, "Key should have expired")
+	require.Equal(t, redis.Nil, err)
 
 	t.Log("Key successfully expired")
 }
 
 // TestRedisListOperations demonstrates list operations
 func TestRedisListOperations(t *testing.T) {
+	t.Parallel()
+
 	ctx := context.Background()
 
-	redisContainer, err := tcredis.Run(ctx, "redis:7-alpine")
+	redisContainer, err := tcredis.Run(ctx, images.Redis.Ref(), recordStartup(t))
 	testcontainers.CleanupContainer(t, redisContainer)
 	require.NoError(t, err)
 
@@ -121,9 +153,11 @@ func TestRedisListOperations(t *testing.T) {
 
 // TestRedisHashOperations demonstrates hash operations
 func TestRedisHashOperations(t *testing.T) {
+	t.Parallel()
+
 	ctx := context.Background()
 
-	redisContainer, err := tcredis.Run(ctx, "redis:7-alpine")
+	redisContainer, err := tcredis.Run(ctx, images.Redis.Ref(), recordStartup(t))
 	testcontainers.CleanupContainer(t, redisContainer)
 	require.NoError(t, err)
 
@@ -161,14 +195,18 @@ func TestRedisHashOperations(t *testing.T) {
 
 // TestRedisWithConfig demonstrates using Redis with custom configuration
 func TestRedisWithConfig(t *testing.T) {
+	t.Parallel()
+
 	ctx := context.Background()
 
 	// Start Redis with snapshotting and verbose logging
 	redisContainer, err := tcredis.Run(
 		ctx,
-		"redis:7-alpine",
-		tcredis.WithSnapshotting(10, 1), // Save after 1 key changes within 10 seconds
+		images.Redis.Ref(),
+		// Save after 1 key changes within 10 seconds, see TestRedisSnapshotPersistence
+		tcredis.WithSnapshotting(10, 1),
 		tcredis.WithLogLevel(tcredis.LogLevelVerbose),
+		recordStartup(t),
 	)
 	testcontainers.CleanupContainer(t, redisContainer)
 	require.NoError(t, err)
@@ -189,3 +227,135 @@ func TestRedisWithConfig(t *testing.T) {
 
 	t.Log("Redis running with custom configuration")
 }
+
+// connectRedis connects to the Redis container. The mapped port changes
+// when a container is restarted, so it is looked up again after Start.
+func connectRedis(t *testing.T, redisContainer *tcredis.RedisContainer) *redis.Client {
+	t.Helper()
+
+	connStr, err := redisContainer.ConnectionString(context.Background())
+	require.NoError(t, err)
+
+	opt, err := redis.ParseURL(connStr)
+	require.NoError(t, err)
+
+	client := redis.NewClient(opt)
+	t.Cleanup(func() { client.Close() })
+	return client
+}
+
+// TestRedisSnapshotPersistence demonstrates that RDB snapshots survive a restart
+func TestRedisSnapshotPersistence(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	redisContainer, err := tcredis.Run(
+		ctx,
+		images.Redis.Ref(),
+		tcredis.WithSnapshotting(10, 1),
+		// Runs again on Start, and waits for Redis to load its dataset
+		testcontainers.WithWaitStrategy(waitx.ForRedisPing("6379/tcp")),
+		recordStartup(t),
+	)
+	testcontainers.CleanupContainer(t, redisContainer)
+	require.NoError(t, err)
+
+	client := connectRedis(t, redisContainer)
+
+	for i := range 100 {
+		require.NoError(t, client.Set(ctx, fmt.Sprintf("key:%d", i), i, 0).Err())
+	}
+
+	// Snapshot in the background and wait for the dump to be written: the
+	// change counter only drops back to 0 once a save succeeds, whereas
+	// LASTSAVE has a one-second resolution and may not move.
+	require.NoError(t, client.BgSave(ctx).Err())
+
+	require.Eventually(t, func() bool {
+		info, err := client.Info(ctx, "persistence").Result()
+		return err == nil &&
+			strings.Contains(info, "rdb_changes_since_last_save:0\r\n") &&
+			strings.Contains(info, "rdb_bgsave_in_progress:0") &&
+			strings.Contains(info, "rdb_last_bgsave_status:ok")
+	}, 10*time.Second, 100*time.Millisecond, "BGSAVE did not complete")
+
+	// Redis saves again on shutdown when save points are set. Remove them,
+	// so that the data can only come back from the BGSAVE snapshot: a key
+	// written after it is lost.
+	require.NoError(t, client.ConfigSet(ctx, "save", "").Err())
+	require.NoError(t, client.Set(ctx, "unsaved", "lost", 0).Err())
+
+	require.NoError(t, redisContainer.Stop(ctx, nil))
+	require.NoError(t, redisContainer.Start(ctx))
+
+	client = connectRedis(t, redisContainer)
+
+	size, err := client.DBSize(ctx).Result()
+	require.NoError(t, err)
+	require.Equal(t, int64(100), size)
+
+	val, err := client.Get(ctx, "key:42").Result()
+	require.NoError(t, err)
+	require.Equal(t, "42", val)
+
+	_, err = client.Get(ctx, "unsaved").Result()
+	require.Equal(t, redis.Nil, err)
+
+	t.Log("Keys saved by BGSAVE survived a restart of the container")
+}
+
+// TestRedisAOFPersistence demonstrates append-only file persistence configured
+// with a redis.conf
+func TestRedisAOFPersistence(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	redisContainer, err := tcredis.Run(
+		ctx,
+		images.Redis.Ref(),
+		tcredis.WithConfigFile("testdata/redis/redis-aof.conf"),
+		testcontainers.WithWaitStrategy(waitx.ForRedisPing("6379/tcp")),
+		recordStartup(t),
+	)
+	testcontainers.CleanupContainer(t, redisContainer)
+	require.NoError(t, err)
+
+	client := connectRedis(t, redisContainer)
+
+	info, err := client.Info(ctx, "persistence").Result()
+	require.NoError(t, err)
+	require.Contains(t, info, "aof_enabled:1")
+
+	// Every write is fsynced, up to the last one before the stop
+	for range 10 {
+		require.NoError(t, client.Incr(ctx, "counter").Err())
+	}
+	require.NoError(t, client.RPush(ctx, "events", "created", "updated", "deleted").Err())
+
+	require.NoError(t, redisContainer.Stop(ctx, nil))
+	require.NoError(t, redisContainer.Start(ctx))
+
+	client = connectRedis(t, redisContainer)
+
+	counter, err := client.Get(ctx, "counter").Int()
+	require.NoError(t, err)
+	require.Equal(t, 10, counter)
+
+	events, err := client.LRange(ctx, "events", 0, -1).Result()
+	require.NoError(t, err)
+	require.Equal(t, []string{"created", "updated", "deleted"}, events)
+
+	// No snapshot was taken: the data was replayed from the AOF
+	exitCode, reader, err := redisContainer.Exec(ctx, []string{"ls", "/data"}, exec.Multiplexed())
+	require.NoError(t, err)
+	require.Equal(t, 0, exitCode)
+
+	files, err := io.ReadAll(reader)
+	require.NoError(t, err)
+	require.Contains(t, string(files), "appendonlydir")
+	require.NotContains(t, string(files), "dump.rdb")
+
+	t.Log("Writes logged to the AOF survived a restart of the container")
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"regexp"
 	"strings"
 	"testing"
 	"time"
@@ -13,20 +14,26 @@ import (
 	"github.com/testcontainers/testcontainers-go"
 	"github.com/testcontainers/testcontainers-go/exec"
 	"github.com/testcontainers/testcontainers-go/wait"
+
+	"github.com/testcontainers/testcontainers-go/examples/internal/images"
+	"github.com/testcontainers/testcontainers-go/examples/internal/logcapture"
 )
 
 // TestGenericNginx demonstrates using a generic container with nginx
 func TestGenericNginx(t *testing.T) {
+	t.Parallel()
+
 	ctx := context.Background()
 
 	// Start nginx container
 	nginxContainer, err := testcontainers.Run(
 		ctx,
-		"nginx:alpine",
+		images.Nginx.Ref(),
 		testcontainers.WithExposedPorts("80/tcp"),
 		testcontainers.WithWaitStrategy(
 			wait.ForListeningPort("80/tcp").WithStartupTimeout(30*time.Second),
 		),
+		recordStartup(t),
 	)
 	testcontainers.CleanupContainer(t, nginxContainer)
 	require.NoError(t, err)
@@ -51,6 +58,8 @@ func TestGenericNginx(t *testing.T) {
 
 // TestGenericContainerWithCustomHTML demonstrates serving custom content with nginx
 func TestGenericContainerWithCustomHTML(t *testing.T) {
+	t.Parallel()
+
 	ctx := context.Background()
 
 	customHTML := `<!DOCTYPE html>
@@ -62,7 +71,7 @@ func TestGenericContainerWithCustomHTML(t *testing.T) {
 	// Start nginx with custom HTML
 	nginxContainer, err := testcontainers.Run(
 		ctx,
-		"nginx:alpine",
+		images.Nginx.Ref(),
 		testcontainers.WithExposedPorts("80/tcp"),
 		testcontainers.WithFiles(testcontainers.ContainerFile{
 			Reader:            strings.NewReader(customHTML),
@@ -72,6 +81,7 @@ func TestGenericContainerWithCustomHTML(t *testing.T) {
 		testcontainers.WithWaitStrategy(
 			wait.ForListeningPort("80/tcp"),
 		),
+		recordStartup(t),
 	)
 	testcontainers.CleanupContainer(t, nginxContainer)
 	require.NoError(t, err)
@@ -92,17 +102,20 @@ func TestGenericContainerWithCustomHTML(t *testing.T) {
 
 // TestGenericContainerWithEnv demonstrates using environment variables
 func TestGenericContainerWithEnv(t *testing.T) {
+	t.Parallel()
+
 	ctx := context.Background()
 
 	// Start alpine container that echoes an environment variable
-	alpineContainer, err := testcontainers.Run(
+	alpineContainer, err := runContainer(
 		ctx,
-		"alpine:latest",
+		images.Alpine.Ref(),
 		testcontainers.WithEnv(map[string]string{
 			"MY_VAR":      "test_value",
 			"ANOTHER_VAR": "another_value",
 		}),
 		testcontainers.WithCmd("sleep", "300"),
+		recordStartup(t),
 	)
 	testcontainers.CleanupContainer(t, alpineContainer)
 	require.NoError(t, err)
@@ -121,20 +134,22 @@ func TestGenericContainerWithEnv(t *testing.T) {
 
 // TestGenericContainerWithCommand demonstrates running a custom command
 func TestGenericContainerWithCommand(t *testing.T) {
+	t.Parallel()
+
 	ctx := context.Background()
 
 	// Start alpine with a custom command that creates a file
-	alpineContainer, err := testcontainers.Run(
+	alpineContainer, err := runContainer(
 		ctx,
-		"alpine:latest",
-		testcontainers.WithCmd("sh", "-c", "echo 'Hello' > /tmp/hello.txt && sleep 300"),
+		images.Alpine.Ref(),
+		testcontainers.WithCmd("sh", "-c", "echo 'Hello' > /tmp/hello.txt && echo 'File created' && sleep 300"),
+		// The command logs once the file exists
+		testcontainers.WithWaitStrategy(wait.ForLog("File created")),
+		recordStartup(t),
 	)
 	testcontainers.CleanupContainer(t, alpineContainer)
 	require.NoError(t, err)
 
-	// Give it a moment to create the file
-	time.Sleep(1 * time.Second)
-
 	// Read the file we created
 	exitCode, reader, err := alpineContainer.Exec(ctx, []string{"cat", "/tmp/hello.txt"}, exec.Multiplexed())
 	require.NoError(t, err)
@@ -149,17 +164,20 @@ func TestGenericContainerWithCommand(t *testing.T) {
 
 // TestGenericContainerWithLabels demonstrates using labels
 func TestGenericContainerWithLabels(t *testing.T) {
+	t.Parallel()
+
 	ctx := context.Background()
 
 	alpineContainer, err := testcontainers.Run(
 		ctx,
-		"alpine:latest",
+		images.Alpine.Ref(),
 		testcontainers.WithLabels(map[string]string{
 			"app":         "testapp",
 			"environment": "test",
 			"version":     "1.0",
 		}),
 		testcontainers.WithCmd("sleep", "300"),
+		recordStartup(t),
 	)
 	testcontainers.CleanupContainer(t, alpineContainer)
 	require.NoError(t, err)
@@ -177,15 +195,18 @@ func TestGenericContainerWithLabels(t *testing.T) {
 
 // TestGenericContainerWithTmpfs demonstrates using temporary filesystems
 func TestGenericContainerWithTmpfs(t *testing.T) {
+	t.Parallel()
+
 	ctx := context.Background()
 
 	alpineContainer, err := testcontainers.Run(
 		ctx,
-		"alpine:latest",
+		images.Alpine.Ref(),
 		testcontainers.WithTmpfs(map[string]string{
 			"/tmp": "rw,size=100m",
 		}),
 		testcontainers.WithCmd("sleep", "300"),
+		recordStartup(t),
 	)
 	testcontainers.CleanupContainer(t, alpineContainer)
 	require.NoError(t, err)
@@ -202,45 +223,50 @@ func TestGenericContainerWithTmpfs(t *testing.T) {
 	t.Log("Successfully mounted tmpfs in container")
 }
 
-// TestGenericContainerLogs demonstrates accessing container logs
+// TestGenericContainerLogs demonstrates capturing container logs
 func TestGenericContainerLogs(t *testing.T) {
+	t.Parallel()
+
 	ctx := context.Background()
 
+	// Capture stdout and stderr separately; they are only written to the
+	// test output if the test fails
+	logs := logcapture.New(t, "alpine")
+
 	// Start container that produces logs
-	alpineContainer, err := testcontainers.Run(
+	alpineContainer, err := runContainer(
 		ctx,
-		"alpine:latest",
-		testcontainers.WithCmd("sh", "-c", "echo 'Starting...'; sleep 1; echo 'Running...'; sleep 300"),
+		images.Alpine.Ref(),
+		testcontainers.WithCmd("sh", "-c", "echo 'Starting...'; echo 'Warming up' >&2; sleep 1; echo 'Running...'; sleep 300"),
+		testcontainers.WithLogConsumers(logs),
+		// Wait until the last line we assert on has been written
+		testcontainers.WithWaitStrategy(wait.ForLog("Running...")),
+		recordStartup(t),
 	)
 	testcontainers.CleanupContainer(t, alpineContainer)
 	require.NoError(t, err)
 
-	// Wait a moment for logs to be written
-	time.Sleep(2 * time.Second)
+	// Consumers receive the output asynchronously, one stream at a time
+	logs.RequireLine(regexp.MustCompile(`^Running\.\.\.$`), 10*time.Second)
+	logs.RequireLine(regexp.MustCompile(`^Warming up$`), 10*time.Second)
 
-	// Read logs
-	logs, err := alpineContainer.Logs(ctx)
-	require.NoError(t, err)
-	defer logs.Close()
-
-	logContent, err := io.ReadAll(logs)
-	require.NoError(t, err)
-
-	logStr := string(logContent)
-	require.Contains(t, logStr, "Starting...")
-	require.Contains(t, logStr, "Running...")
+	require.Equal(t, "Starting...\nRunning...\n", logs.Stdout())
+	require.Equal(t, "Warming up\n", logs.Stderr())
 
-	t.Log("Successfully read container logs")
+	t.Log("Successfully captured container logs")
 }
 
 // TestGenericContainerExec demonstrates executing commands in a running container
 func TestGenericContainerExec(t *testing.T) {
+	t.Parallel()
+
 	ctx := context.Background()
 
-	alpineContainer, err := testcontainers.Run(
+	alpineContainer, err := runContainer(
 		ctx,
-		"alpine:latest",
+		images.Alpine.Ref(),
 		testcontainers.WithCmd("sleep", "300"),
+		recordStartup(t),
 	)
 	testcontainers.CleanupContainer(t, alpineContainer)
 	require.NoError(t, err)
@@ -268,28 +294,37 @@ func TestGenericContainerExec(t *testing.T) {
 		},
 	}
 
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			exitCode, reader, err := alpineContainer.Exec(ctx, tt.cmd, exec.Multiplexed())
-			require.NoError(t, err)
-			require.Equal(t, 0, exitCode)
-
-			output, err := io.ReadAll(reader)
-			require.NoError(t, err)
-			require.Contains(t, string(output), tt.expected)
-		})
-	}
+	// The commands only read the container, so the subtests can share it
+	// and run in parallel. The group returns once they are done, and the
+	// container is terminated when the test ends.
+	t.Run("group", func(t *testing.T) {
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				t.Parallel()
+
+				exitCode, reader, err := alpineContainer.Exec(ctx, tt.cmd, exec.Multiplexed())
+				require.NoError(t, err)
+				require.Equal(t, 0, exitCode)
+
+				output, err := io.ReadAll(reader)
+				require.NoError(t, err)
+				require.Contains(t, string(output), tt.expected)
+			})
+		}
+	})
 
 	t.Log("Successfully executed multiple commands")
 }
 
 // TestGenericContainerHTTPWait demonstrates waiting for an HTTP endpoint
 func TestGenericContainerHTTPWait(t *testing.T) {
+	t.Parallel()
+
 	ctx := context.Background()
 
 	nginxContainer, err := testcontainers.Run(
 		ctx,
-		"nginx:alpine",
+		images.Nginx.Ref(),
 		testcontainers.WithExposedPorts("80/tcp"),
 		testcontainers.WithWaitStrategy(
 			wait.ForListeningPort("80/tcp"),
@@ -300,6 +335,7 @@ func TestGenericContainerHTTPWait(t *testing.T) {
 				}).
 				WithStartupTimeout(30*time.Second),
 		),
+		recordStartup(t),
 	)
 	testcontainers.CleanupContainer(t, nginxContainer)
 	require.NoError(t, err)
@@ -319,11 +355,13 @@ func TestGenericContainerHTTPWait(t *testing.T) {
 
 // TestGenericContainerLogWait demonstrates waiting for a log message
 func TestGenericContainerLogWait(t *testing.T) {
+	t.Parallel()
+
 	ctx := context.Background()
 
-	alpineContainer, err := testcontainers.Run(
+	alpineContainer, err := runContainer(
 		ctx,
-		"alpine:latest",
+		images.Alpine.Ref(),
 		testcontainers.WithCmd(
 			"sh", "-c",
 			"echo 'Initializing...'; sleep 2; echo 'Ready!'; sleep 300",
@@ -331,6 +369,7 @@ func TestGenericContainerLogWait(t *testing.T) {
 		testcontainers.WithWaitStrategy(
 			wait.ForLog("Ready!").WithStartupTimeout(10*time.Second),
 		),
+		recordStartup(t),
 	)
 	testcontainers.CleanupContainer(t, alpineContainer)
 	require.NoError(t, err)
@@ -341,13 +380,17 @@ func TestGenericContainerLogWait(t *testing.T) {
 
 // TestGenericContainerPortInfo demonstrates getting port information
 func TestGenericContainerPortInfo(t *testing.T) {
+	t.Parallel()
+
 	ctx := context.Background()
 
 	nginxContainer, err := testcontainers.Run(
 		ctx,
-		"nginx:alpine",
-		testcontainers.WithExposedPorts("80/tcp", "443/tcp"),
+		images.Nginx.Ref(),
+		// nginx:alpine only serves plain HTTP, so 443 would never be ready
+		testcontainers.WithExposedPorts("80/tcp"),
 		testcontainers.WithWaitStrategy(wait.ForListeningPort("80/tcp")),
+		recordStartup(t),
 	)
 	testcontainers.CleanupContainer(t, nginxContainer)
 	require.NoError(t, err)
@@ -0,0 +1,234 @@
+package examples_test
+
+import (
+	"context"
+	"net"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+	"github.com/stretchr/testify/require"
+	"github.com/testcontainers/testcontainers-go"
+	tckafka "github.com/testcontainers/testcontainers-go/modules/kafka"
+
+	"github.com/testcontainers/testcontainers-go/examples/internal/images"
+)
+
+// TestKafkaMessaging demonstrates producing and consuming Kafka messages
+func TestKafkaMessaging(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	// Start Kafka container
+	kafkaContainer, err := tckafka.Run(
+		ctx,
+		images.Kafka.Ref(),
+		tckafka.WithClusterID("test-cluster"),
+		recordStartup(t),
+	)
+	testcontainers.CleanupContainer(t, kafkaContainer)
+	require.NoError(t, err)
+
+	// Get bootstrap servers
+	brokers, err := kafkaContainer.Brokers(ctx)
+	require.NoError(t, err)
+
+	t.Run("CreateTopic", func(t *testing.T) {
+		createTopic(t, brokers, "create-topic", 3)
+
+		conn, err := kafka.DialContext(ctx, "tcp", brokers[0])
+		require.NoError(t, err)
+		defer conn.Close()
+
+		partitions, err := conn.ReadPartitions("create-topic")
+		require.NoError(t, err)
+		require.Len(t, partitions, 3)
+	})
+
+	t.Run("ConsumerGroups", func(t *testing.T) {
+		topic := "consumer-groups"
+		createTopic(t, brokers, topic, 1)
+
+		writer := newWriter(brokers, topic)
+		defer writer.Close()
+
+		err := writer.WriteMessages(ctx,
+			kafka.Message{Value: []byte("message-1")},
+			kafka.Message{Value: []byte("message-2")},
+			kafka.Message{Value: []byte("message-3")},
+		)
+		require.NoError(t, err)
+
+		// The first member of group-a reads and commits everything produced so far
+		readerA := newGroupReader(brokers, topic, "group-a")
+		require.Equal(t, []string{"message-1", "message-2", "message-3"}, readValues(t, readerA, 3))
+		require.NoError(t, readerA.Close())
+
+		err = writer.WriteMessages(ctx, kafka.Message{Value: []byte("message-4")})
+		require.NoError(t, err)
+
+		// A new member of group-a resumes from the committed offset
+		readerA = newGroupReader(brokers, topic, "group-a")
+		defer readerA.Close()
+		require.Equal(t, []string{"message-4"}, readValues(t, readerA, 1))
+
+		// group-b has no committed offset, so it starts from the beginning
+		readerB := newGroupReader(brokers, topic, "group-b")
+		defer readerB.Close()
+		require.Equal(t, []string{"message-1", "message-2", "message-3", "message-4"}, readValues(t, readerB, 4))
+	})
+
+	t.Run("OrderingPerKey", func(t *testing.T) {
+		topic := "ordering-per-key"
+		createTopic(t, brokers, topic, 3)
+
+		writer := newWriter(brokers, topic)
+		defer writer.Close()
+
+		// Interleave the keys so that ordering only holds within each key
+		keys := []string{"customer-1", "customer-2", "customer-3"}
+		var messages []kafka.Message
+		for seq := range 5 {
+			for _, key := range keys {
+				messages = append(messages, kafka.Message{
+					Key:   []byte(key),
+					Value: []byte(strconv.Itoa(seq)),
+				})
+			}
+		}
+		err := writer.WriteMessages(ctx, messages...)
+		require.NoError(t, err)
+
+		reader := newGroupReader(brokers, topic, "ordering")
+		defer reader.Close()
+
+		readCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
+		defer cancel()
+
+		next := make(map[string]int)
+		partition := make(map[string]int)
+		for range messages {
+			msg, err := reader.ReadMessage(readCtx)
+			require.NoError(t, err)
+
+			key := string(msg.Key)
+			require.Equal(t, strconv.Itoa(next[key]), string(msg.Value), "messages of %s out of order", key)
+			next[key]++
+
+			// The hash balancer always routes a key to the same partition
+			if p, ok := partition[key]; ok {
+				require.Equal(t, p, msg.Partition, "key %s moved partition", key)
+			}
+			partition[key] = msg.Partition
+		}
+
+		for _, key := range keys {
+			require.Equal(t, 5, next[key])
+		}
+	})
+
+	t.Run("OffsetReset", func(t *testing.T) {
+		topic := "offset-reset"
+		createTopic(t, brokers, topic, 1)
+
+		writer := newWriter(brokers, topic)
+		defer writer.Close()
+
+		err := writer.WriteMessages(ctx,
+			kafka.Message{Value: []byte("message-0")},
+			kafka.Message{Value: []byte("message-1")},
+			kafka.Message{Value: []byte("message-2")},
+		)
+		require.NoError(t, err)
+
+		// Offsets can only be moved by readers outside a consumer group
+		reader := kafka.NewReader(kafka.ReaderConfig{
+			Brokers:   brokers,
+			Topic:     topic,
+			Partition: 0,
+		})
+		defer reader.Close()
+
+		require.Equal(t, []string{"message-0", "message-1", "message-2"}, readValues(t, reader, 3))
+
+		// Replay the partition from the beginning
+		require.NoError(t, reader.SetOffset(kafka.FirstOffset))
+		require.Equal(t, []string{"message-0"}, readValues(t, reader, 1))
+
+		// Jump to a specific offset
+		require.NoError(t, reader.SetOffset(2))
+		require.Equal(t, []string{"message-2"}, readValues(t, reader, 1))
+		require.Equal(t, int64(3), reader.Offset())
+	})
+}
+
+// createTopic creates a topic through the controller and waits until its
+// partitions are visible in the cluster metadata.
+func createTopic(t *testing.T, brokers []string, topic string, partitions int) {
+	t.Helper()
+
+	conn, err := kafka.Dial("tcp", brokers[0])
+	require.NoError(t, err)
+	defer conn.Close()
+
+	controller, err := conn.Controller()
+	require.NoError(t, err)
+
+	controllerConn, err := kafka.Dial("tcp", net.JoinHostPort(controller.Host, strconv.Itoa(controller.Port)))
+	require.NoError(t, err)
+	defer controllerConn.Close()
+
+	err = controllerConn.CreateTopics(kafka.TopicConfig{
+		Topic:             topic,
+		NumPartitions:     partitions,
+		ReplicationFactor: 1,
+	})
+	require.NoError(t, err)
+
+	require.Eventually(t, func() bool {
+		p, err := conn.ReadPartitions(topic)
+		return err == nil && len(p) == partitions
+	}, 10*time.Second, 100*time.Millisecond, "topic %s was not created", topic)
+}
+
+// newWriter returns a producer that routes messages by key and waits for
+// the broker to acknowledge them.
+func newWriter(brokers []string, topic string) *kafka.Writer {
+	return &kafka.Writer{
+		Addr:         kafka.TCP(brokers...),
+		Topic:        topic,
+		Balancer:     &kafka.Hash{},
+		RequiredAcks: kafka.RequireAll,
+		BatchTimeout: 10 * time.Millisecond,
+	}
+}
+
+// newGroupReader returns a consumer group member that commits each message
+// as soon as it is read.
+func newGroupReader(brokers []string, topic, groupID string) *kafka.Reader {
+	return kafka.NewReader(kafka.ReaderConfig{
+		Brokers:     brokers,
+		Topic:       topic,
+		GroupID:     groupID,
+		StartOffset: kafka.FirstOffset,
+		MaxWait:     100 * time.Millisecond,
+	})
+}
+
+// readValues reads n messages from reader and returns their values.
+func readValues(t *testing.T, reader *kafka.Reader, n int) []string {
+	t.Helper()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	values := make([]string, 0, n)
+	for range n {
+		msg, err := reader.ReadMessage(ctx)
+		require.NoError(t, err, "reading message %d of %d", len(values)+1, n)
+		values = append(values, string(msg.Value))
+	}
+	return values
+}
@@ -0,0 +1,283 @@
+package examples_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+
+	toxiproxy "github.com/Shopify/toxiproxy/v2/client"
+	_ "github.com/lib/pq"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/require"
+	"github.com/testcontainers/testcontainers-go"
+	"github.com/testcontainers/testcontainers-go/modules/postgres"
+	tcredis "github.com/testcontainers/testcontainers-go/modules/redis"
+	tctoxiproxy "github.com/testcontainers/testcontainers-go/modules/toxiproxy"
+	"github.com/testcontainers/testcontainers-go/network"
+
+	"github.com/testcontainers/testcontainers-go/examples/internal/images"
+)
+
+// faultyNetwork gives access to PostgreSQL and Redis through toxiproxy
+type faultyNetwork struct {
+	postgres     *toxiproxy.Proxy
+	redis        *toxiproxy.Proxy
+	postgresAddr string
+	redisAddr    string
+}
+
+// startFaultyNetwork starts PostgreSQL and Redis on a custom network, as in
+// TestApplicationWithDependencies, and toxiproxy between them and the test
+func startFaultyNetwork(t *testing.T) *faultyNetwork {
+	t.Helper()
+	ctx := context.Background()
+
+	nw, err := network.New(ctx)
+	testcontainers.CleanupNetwork(t, nw)
+	require.NoError(t, err)
+
+	pgContainer, err := postgres.Run(
+		ctx,
+		images.Postgres.Ref(),
+		postgres.WithDatabase("myapp"),
+		postgres.WithUsername("appuser"),
+		postgres.WithPassword("apppass"),
+		network.WithNetwork([]string{"postgres"}, nw),
+		postgres.BasicWaitStrategies(),
+		recordStartup(t),
+	)
+	testcontainers.CleanupContainer(t, pgContainer)
+	require.NoError(t, err)
+
+	redisContainer, err := tcredis.Run(
+		ctx,
+		images.Redis.Ref(),
+		network.WithNetwork([]string{"redis"}, nw),
+		recordStartup(t),
+	)
+	testcontainers.CleanupContainer(t, redisContainer)
+	require.NoError(t, err)
+
+	// The module declares the proxies in a config file read when toxiproxy
+	// starts, exposes their ports and waits for the control API
+	toxiproxyContainer, err := tctoxiproxy.Run(
+		ctx,
+		images.Toxiproxy.Ref(),
+		tctoxiproxy.WithProxy("postgres", "postgres:5432"),
+		tctoxiproxy.WithProxy("redis", "redis:6379"),
+		network.WithNetwork([]string{"toxiproxy"}, nw),
+		recordStartup(t),
+	)
+	testcontainers.CleanupContainer(t, toxiproxyContainer)
+	require.NoError(t, err)
+
+	apiURI, err := toxiproxyContainer.URI(ctx)
+	require.NoError(t, err)
+
+	client := toxiproxy.NewClient(apiURI)
+
+	n := &faultyNetwork{}
+
+	n.postgres, err = client.Proxy("postgres")
+	require.NoError(t, err)
+
+	n.redis, err = client.Proxy("redis")
+	require.NoError(t, err)
+
+	// Each proxy listens on its own port, from 8666 in the order of the
+	// WithProxy options
+	host, port, err := toxiproxyContainer.ProxiedEndpoint(8666)
+	require.NoError(t, err)
+	n.postgresAddr = net.JoinHostPort(host, port)
+
+	host, port, err = toxiproxyContainer.ProxiedEndpoint(8667)
+	require.NoError(t, err)
+	n.redisAddr = net.JoinHostPort(host, port)
+
+	return n
+}
+
+// openDB connects to PostgreSQL through the proxy
+func (n *faultyNetwork) openDB(t *testing.T) *sql.DB {
+	t.Helper()
+
+	db, err := sql.Open("postgres", fmt.Sprintf("postgres://appuser:apppass@%s/myapp?sslmode=disable", n.postgresAddr))
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+
+	require.NoError(t, db.Ping())
+	return db
+}
+
+// redisClient connects to Redis through the proxy. go-redis retries are
+// disabled so that every fault reaches the test.
+func (n *faultyNetwork) redisClient(t *testing.T, readTimeout time.Duration) *redis.Client {
+	t.Helper()
+
+	client := redis.NewClient(&redis.Options{
+		Addr:        n.redisAddr,
+		ReadTimeout: readTimeout,
+		MaxRetries:  -1,
+	})
+	t.Cleanup(func() { client.Close() })
+
+	require.NoError(t, client.Ping(context.Background()).Err())
+	return client
+}
+
+// TestToxiproxyLatency demonstrates injecting latency and asserting client-side timeouts
+func TestToxiproxyLatency(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	n := startFaultyNetwork(t)
+
+	t.Run("Postgres", func(t *testing.T) {
+		db := n.openDB(t)
+
+		_, err := n.postgres.AddToxic("latency", "latency", "downstream", 1, toxiproxy.Attributes{
+			"latency": 500,
+		})
+		require.NoError(t, err)
+
+		start := time.Now()
+		require.NoError(t, db.Ping())
+		require.GreaterOrEqual(t, time.Since(start), 500*time.Millisecond)
+
+		require.NoError(t, n.postgres.RemoveToxic("latency"))
+
+		start = time.Now()
+		require.NoError(t, db.Ping())
+		require.Less(t, time.Since(start), 500*time.Millisecond)
+	})
+
+	t.Run("RedisTimeout", func(t *testing.T) {
+		client := n.redisClient(t, 200*time.Millisecond)
+
+		_, err := n.redis.AddToxic("latency", "latency", "downstream", 1, toxiproxy.Attributes{
+			"latency": 1000,
+		})
+		require.NoError(t, err)
+		defer func() {
+			require.NoError(t, n.redis.RemoveToxic("latency"))
+		}()
+
+		// The client gives up long before the reply arrives
+		start := time.Now()
+		err = client.Ping(ctx).Err()
+		require.Less(t, time.Since(start), time.Second)
+
+		var netErr net.Error
+		require.ErrorAs(t, err, &netErr)
+		require.True(t, netErr.Timeout(), "expected a timeout, got %v", err)
+	})
+}
+
+// TestToxiproxyBandwidth demonstrates limiting the bandwidth of a connection
+func TestToxiproxyBandwidth(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	n := startFaultyNetwork(t)
+
+	client := n.redisClient(t, 10*time.Second)
+
+	// Only the replies are throttled, so the value is written at full speed
+	value := strings.Repeat("x", 64*1024)
+	require.NoError(t, client.Set(ctx, "large", value, 0).Err())
+
+	_, err := n.redis.AddToxic("bandwidth", "bandwidth", "downstream", 1, toxiproxy.Attributes{
+		"rate": 32, // KB/s
+	})
+	require.NoError(t, err)
+
+	// 64 KB at 32 KB/s takes about two seconds
+	start := time.Now()
+	got, err := client.Get(ctx, "large").Result()
+	require.NoError(t, err)
+	require.Equal(t, value, got)
+	require.GreaterOrEqual(t, time.Since(start), time.Second)
+
+	// A client with a tighter read timeout cannot receive the value in time
+	impatient := n.redisClient(t, 500*time.Millisecond)
+	err = impatient.Get(ctx, "large").Err()
+
+	var netErr net.Error
+	require.ErrorAs(t, err, &netErr)
+	require.True(t, netErr.Timeout(), "expected a timeout, got %v", err)
+}
+
+// TestToxiproxyConnectionReset demonstrates connection resets, outages and retries
+func TestToxiproxyConnectionReset(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	n := startFaultyNetwork(t)
+
+	t.Run("Reset", func(t *testing.T) {
+		db := n.openDB(t)
+		client := n.redisClient(t, time.Second)
+
+		for _, proxy := range []*toxiproxy.Proxy{n.postgres, n.redis} {
+			_, err := proxy.AddToxic("reset", "reset_peer", "downstream", 1, toxiproxy.Attributes{
+				"timeout": 0,
+			})
+			require.NoError(t, err)
+		}
+
+		require.Error(t, db.Ping())
+
+		err := client.Ping(ctx).Err()
+		require.True(t, errors.Is(err, syscall.ECONNRESET) || errors.Is(err, io.EOF), "expected a reset connection, got %v", err)
+
+		for _, proxy := range []*toxiproxy.Proxy{n.postgres, n.redis} {
+			require.NoError(t, proxy.RemoveToxic("reset"))
+		}
+
+		// database/sql replaces the broken connections, go-redis too
+		require.NoError(t, db.Ping())
+		require.NoError(t, client.Ping(ctx).Err())
+	})
+
+	t.Run("RetryDuringOutage", func(t *testing.T) {
+		client := n.redisClient(t, time.Second)
+
+		// Take Redis down and bring it back after a second
+		require.NoError(t, n.redis.Disable())
+
+		enabled := make(chan error, 1)
+		time.AfterFunc(time.Second, func() {
+			enabled <- n.redis.Enable()
+		})
+
+		attempts, err := retry(10, 100*time.Millisecond, func() error {
+			return client.Ping(ctx).Err()
+		})
+		require.NoError(t, <-enabled)
+		require.NoError(t, err)
+		require.Greater(t, attempts, 1, "the first attempt should fail during the outage")
+
+		t.Logf("Redis answered after %d attempts", attempts)
+	})
+}
+
+// retry calls fn until it succeeds or the attempts are exhausted, doubling
+// the delay after each failure. It returns the number of attempts made.
+func retry(attempts int, delay time.Duration, fn func() error) (int, error) {
+	var err error
+	for i := 1; i <= attempts; i++ {
+		if err = fn(); err == nil {
+			return i, nil
+		}
+		time.Sleep(delay)
+		delay *= 2
+	}
+	return attempts, err
+}
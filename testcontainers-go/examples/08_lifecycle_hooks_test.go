@@ -0,0 +1,134 @@
+package examples_test
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"testing"
+
+	_ "github.com/lib/pq"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/require"
+	"github.com/testcontainers/testcontainers-go"
+	"github.com/testcontainers/testcontainers-go/exec"
+	"github.com/testcontainers/testcontainers-go/modules/postgres"
+	tcredis "github.com/testcontainers/testcontainers-go/modules/redis"
+
+	"github.com/testcontainers/testcontainers-go/examples/internal/images"
+	"github.com/testcontainers/testcontainers-go/examples/internal/lifecycle"
+)
+
+// TestPostgresLifecycleHooks demonstrates running migrations and collecting
+// diagnostics from lifecycle hooks
+func TestPostgresLifecycleHooks(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	// Registered before the container cleanup, so it runs after the
+	// container has been terminated and reports every phase
+	timings := lifecycle.NewTimings()
+	t.Cleanup(func() {
+		t.Logf("PostgreSQL lifecycle:\n%s", timings)
+	})
+
+	pgContainer, err := postgres.Run(
+		ctx,
+		images.Postgres.Ref(),
+		postgres.WithDatabase("myapp"),
+		postgres.BasicWaitStrategies(),
+		// Added to the hooks of the module rather than replacing them
+		testcontainers.WithAdditionalLifecycleHooks(
+			timings.Hooks(),
+			// Applies testdata/migrations once the container is ready
+			lifecycle.PostgresMigrations(os.DirFS("testdata/migrations")),
+			// Logs pg_stat_activity before the container stops, if the test failed
+			lifecycle.PostgresDiagnostics(t),
+		),
+		recordStartup(t),
+	)
+	testcontainers.CleanupContainer(t, pgContainer)
+	require.NoError(t, err)
+
+	connStr, err := pgContainer.ConnectionString(ctx, "sslmode=disable")
+	require.NoError(t, err)
+
+	db, err := sql.Open("postgres", connStr)
+	require.NoError(t, err)
+	defer db.Close()
+
+	// The schema and seed data exist as soon as Run returns
+	var count int
+	err = db.QueryRow("SELECT COUNT(*) FROM users").Scan(&count)
+	require.NoError(t, err)
+	require.Equal(t, 2, count)
+
+	t.Log("Migrations were applied by a PostReadies hook")
+}
+
+// TestRedisLifecycleHooks demonstrates a custom hook seeding data and
+// collecting diagnostics from lifecycle hooks
+func TestRedisLifecycleHooks(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	timings := lifecycle.NewTimings()
+	t.Cleanup(func() {
+		t.Logf("Redis lifecycle:\n%s", timings)
+	})
+
+	redisContainer, err := tcredis.Run(
+		ctx,
+		images.Redis.Ref(),
+		testcontainers.WithAdditionalLifecycleHooks(
+			timings.Hooks(),
+			// A hook is a plain function: this one seeds a key once Redis is ready
+			testcontainers.ContainerLifecycleHooks{
+				PostReadies: []testcontainers.ContainerHook{
+					func(ctx context.Context, ctr testcontainers.Container) error {
+						cmd := []string{"redis-cli", "SET", "feature:new-checkout", "on"}
+						code, reader, err := ctr.Exec(ctx, cmd, exec.Multiplexed())
+						if err != nil {
+							return fmt.Errorf("exec %q: %w", cmd, err)
+						}
+						// Exec only fails if the command could not be run: a
+						// failing command is reported by its exit code
+						if code != 0 {
+							output, _ := io.ReadAll(reader)
+							return fmt.Errorf("exec %q: exit code %d: %s", cmd, code, strings.TrimSpace(string(output)))
+						}
+						return nil
+					},
+				},
+			},
+			// Logs INFO before the container stops, if the test failed
+			lifecycle.RedisDiagnostics(t),
+		),
+		recordStartup(t),
+	)
+	testcontainers.CleanupContainer(t, redisContainer)
+	require.NoError(t, err)
+
+	connStr, err := redisContainer.ConnectionString(ctx)
+	require.NoError(t, err)
+
+	opt, err := redis.ParseURL(connStr)
+	require.NoError(t, err)
+
+	client := redis.NewClient(opt)
+	defer client.Close()
+
+	val, err := client.Get(ctx, "feature:new-checkout").Result()
+	require.NoError(t, err)
+	require.Equal(t, "on", val)
+
+	// The phases up to ready are recorded once Run returns
+	require.Positive(t, timings.Duration(lifecycle.PhaseStart))
+	require.Positive(t, timings.Duration(lifecycle.PhaseReady))
+
+	t.Log("Redis was seeded by a PostReadies hook")
+}
@@ -0,0 +1,367 @@
+package examples_test
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/testcontainers/testcontainers-go"
+	"github.com/testcontainers/testcontainers-go/exec"
+	"github.com/testcontainers/testcontainers-go/network"
+
+	"github.com/testcontainers/testcontainers-go/examples/internal/images"
+	"github.com/testcontainers/testcontainers-go/examples/waitx"
+)
+
+const (
+	redisPort    = "6379/tcp"
+	sentinelPort = "26379/tcp"
+
+	// sentinelMaster is the name Sentinel monitors the primary under
+	sentinelMaster = "mymaster"
+)
+
+// redisTopology is a set of Redis containers on a custom network. The
+// nodes announce their addresses on that network, which the test cannot
+// reach, so clients dial through remap.
+type redisTopology struct {
+	// nodes are keyed by the address they announce
+	nodes map[string]testcontainers.Container
+	// hostAddrs maps the announced addresses to the mapped ports
+	hostAddrs map[string]string
+}
+
+// startNode starts a Redis server running cmd on nw, and records it
+// under its address on the network
+func (rt *redisTopology) startNode(t *testing.T, nw *testcontainers.DockerNetwork, alias string, cmd ...string) testcontainers.Container {
+	t.Helper()
+	ctx := context.Background()
+
+	ctr, err := testcontainers.Run(
+		ctx,
+		images.Redis.Ref(),
+		testcontainers.WithCmd(cmd...),
+		testcontainers.WithExposedPorts(redisPort),
+		network.WithNetwork([]string{alias}, nw),
+		testcontainers.WithWaitStrategy(waitx.ForRedisPing(redisPort)),
+		recordStartup(t),
+	)
+	testcontainers.CleanupContainer(t, ctr)
+	require.NoError(t, err)
+
+	ip, err := ctr.ContainerIP(ctx)
+	require.NoError(t, err)
+
+	hostAddr, err := ctr.PortEndpoint(ctx, redisPort, "")
+	require.NoError(t, err)
+
+	addr := net.JoinHostPort(ip, "6379")
+	rt.nodes[addr] = ctr
+	rt.hostAddrs[addr] = hostAddr
+
+	return ctr
+}
+
+// remap dials the mapped port of a node when asked for the address the
+// node announces. Other addresses are dialed as they are.
+func (rt *redisTopology) remap(ctx context.Context, network, addr string) (net.Conn, error) {
+	if hostAddr, ok := rt.hostAddrs[addr]; ok {
+		addr = hostAddr
+	}
+
+	var d net.Dialer
+	return d.DialContext(ctx, network, addr)
+}
+
+// startRedisCluster starts six nodes and joins them into a cluster of three
+// primaries with one replica each
+func startRedisCluster(t *testing.T) *redisTopology {
+	t.Helper()
+	ctx := context.Background()
+
+	nw, err := network.New(ctx)
+	testcontainers.CleanupNetwork(t, nw)
+	require.NoError(t, err)
+
+	rt := &redisTopology{
+		nodes:     make(map[string]testcontainers.Container),
+		hostAddrs: make(map[string]string),
+	}
+
+	var first testcontainers.Container
+	for i := range 6 {
+		ctr := rt.startNode(t, nw, fmt.Sprintf("redis-%d", i),
+			"redis-server",
+			"--cluster-enabled", "yes",
+			// A primary is failed over after two seconds without answering
+			"--cluster-node-timeout", "2000",
+			"--appendonly", "no",
+		)
+		if first == nil {
+			first = ctr
+		}
+	}
+
+	// redis-cli assigns the slots and the replicas
+	cmd := []string{"redis-cli", "--cluster", "create"}
+	for addr := range rt.nodes {
+		cmd = append(cmd, addr)
+	}
+	cmd = append(cmd, "--cluster-replicas", "1", "--cluster-yes")
+
+	exitCode, reader, err := first.Exec(ctx, cmd, exec.Multiplexed())
+	require.NoError(t, err)
+	output, err := io.ReadAll(reader)
+	require.NoError(t, err)
+	require.Equal(t, 0, exitCode, "redis-cli --cluster create:\n%s", output)
+
+	// Every node must agree before the cluster serves all slots
+	for addr := range rt.nodes {
+		node := redis.NewClient(&redis.Options{Addr: rt.hostAddrs[addr]})
+		require.Eventually(t, func() bool {
+			info, err := node.ClusterInfo(ctx).Result()
+			return err == nil && strings.Contains(info, "cluster_state:ok")
+		}, 30*time.Second, 200*time.Millisecond, "cluster state of %s", addr)
+		node.Close()
+	}
+
+	return rt
+}
+
+// clusterClient connects to the cluster through the mapped ports
+func (rt *redisTopology) clusterClient(t *testing.T) *redis.ClusterClient {
+	t.Helper()
+
+	var seeds []string
+	for addr := range rt.nodes {
+		seeds = append(seeds, addr)
+	}
+
+	client := redis.NewClusterClient(&redis.ClusterOptions{
+		Addrs:  seeds,
+		Dialer: rt.remap,
+	})
+	t.Cleanup(func() { client.Close() })
+
+	require.NoError(t, client.Ping(context.Background()).Err())
+	return client
+}
+
+// TestRedisCluster demonstrates a six-node Redis Cluster and the failover of a primary
+func TestRedisCluster(t *testing.T) {
+	// Not parallel: the failover timeouts assume the machine is not busy
+	// starting the containers of other tests
+	ctx := context.Background()
+	rt := startRedisCluster(t)
+	client := rt.clusterClient(t)
+
+	t.Run("Sharding", func(t *testing.T) {
+		for i := range 100 {
+			require.NoError(t, client.Set(ctx, fmt.Sprintf("key:%d", i), i, 0).Err())
+		}
+
+		// The keys are spread across the three primaries. ForEachMaster
+		// calls fn concurrently, so fn reports through its error.
+		var primaries atomic.Int32
+		err := client.ForEachMaster(ctx, func(ctx context.Context, primary *redis.Client) error {
+			size, err := primary.DBSize(ctx).Result()
+			if err != nil {
+				return err
+			}
+			if size == 0 {
+				return fmt.Errorf("no key on %s", primary.Options().Addr)
+			}
+			primaries.Add(1)
+			return nil
+		})
+		require.NoError(t, err)
+		require.Equal(t, int32(3), primaries.Load())
+
+		// Keys sharing a hash tag live in the same slot, so they can be
+		// used together
+		pipe := client.TxPipeline()
+		pipe.Set(ctx, "{user:1}:name", "Alice", 0)
+		pipe.Set(ctx, "{user:1}:email", "alice@example.com", 0)
+		_, err = pipe.Exec(ctx)
+		require.NoError(t, err)
+
+		values, err := client.MGet(ctx, "{user:1}:name", "{user:1}:email").Result()
+		require.NoError(t, err)
+		require.Equal(t, []any{"Alice", "alice@example.com"}, values)
+	})
+
+	t.Run("Failover", func(t *testing.T) {
+		require.NoError(t, client.Set(ctx, "failover", "before", 0).Err())
+
+		primary, err := client.MasterForKey(ctx, "failover")
+		require.NoError(t, err)
+
+		// Make sure the replica has the key before losing the primary
+		replicas, err := primary.Wait(ctx, 1, 5*time.Second).Result()
+		require.NoError(t, err)
+		require.Equal(t, int64(1), replicas)
+
+		addr := primary.Options().Addr
+		require.NoError(t, rt.nodes[addr].Stop(ctx, nil))
+		t.Logf("Stopped primary %s", addr)
+
+		// The replica takes over once the node timeout has passed
+		require.EventuallyWithT(t, func(c *assert.CollectT) {
+			val, err := client.Get(ctx, "failover").Result()
+			if assert.NoError(c, err) && assert.Equal(c, "before", val) {
+				assert.NoError(c, client.Set(ctx, "failover", "after", 0).Err())
+			}
+		}, 30*time.Second, 500*time.Millisecond)
+
+		promoted, err := client.MasterForKey(ctx, "failover")
+		require.NoError(t, err)
+		require.NotEqual(t, addr, promoted.Options().Addr)
+
+		t.Logf("Replica %s was promoted", promoted.Options().Addr)
+	})
+}
+
+// startRedisSentinel starts a primary, two replicas and three sentinels
+// monitoring them
+func startRedisSentinel(t *testing.T) (rt *redisTopology, sentinels []string) {
+	t.Helper()
+	ctx := context.Background()
+
+	nw, err := network.New(ctx)
+	testcontainers.CleanupNetwork(t, nw)
+	require.NoError(t, err)
+
+	rt = &redisTopology{
+		nodes:     make(map[string]testcontainers.Container),
+		hostAddrs: make(map[string]string),
+	}
+
+	primary := rt.startNode(t, nw, "redis-primary", "redis-server")
+	primaryIP, err := primary.ContainerIP(ctx)
+	require.NoError(t, err)
+
+	for i := range 2 {
+		rt.startNode(t, nw, fmt.Sprintf("redis-replica-%d", i),
+			"redis-server", "--replicaof", primaryIP, "6379",
+		)
+	}
+
+	// Sentinel rewrites its configuration file, so each gets its own copy.
+	// A quorum of two sentinels marks the primary as down after a second.
+	config := fmt.Sprintf(`port 26379
+sentinel monitor %s %s 6379 2
+sentinel down-after-milliseconds %[1]s 1000
+sentinel failover-timeout %[1]s 5000
+`, sentinelMaster, primaryIP)
+
+	for i := range 3 {
+		ctr, err := testcontainers.Run(
+			ctx,
+			images.Redis.Ref(),
+			testcontainers.WithFiles(testcontainers.ContainerFile{
+				Reader:            strings.NewReader(config),
+				ContainerFilePath: "/data/sentinel.conf",
+				FileMode:          0o644,
+			}),
+			testcontainers.WithCmd("redis-sentinel", "/data/sentinel.conf"),
+			testcontainers.WithExposedPorts(sentinelPort),
+			network.WithNetwork([]string{fmt.Sprintf("redis-sentinel-%d", i)}, nw),
+			testcontainers.WithWaitStrategy(waitx.ForRedisPing(sentinelPort)),
+			recordStartup(t),
+		)
+		testcontainers.CleanupContainer(t, ctr)
+		require.NoError(t, err)
+
+		hostAddr, err := ctr.PortEndpoint(ctx, sentinelPort, "")
+		require.NoError(t, err)
+		sentinels = append(sentinels, hostAddr)
+
+		// Sentinels share the addresses of the others with the client
+		ip, err := ctr.ContainerIP(ctx)
+		require.NoError(t, err)
+		rt.hostAddrs[net.JoinHostPort(ip, "26379")] = hostAddr
+	}
+
+	// Wait until every sentinel knows both replicas and the other sentinels,
+	// so that they can agree on a failover
+	for _, addr := range sentinels {
+		sentinel := redis.NewSentinelClient(&redis.Options{Addr: addr})
+		require.Eventually(t, func() bool {
+			replicas, err := sentinel.Replicas(ctx, sentinelMaster).Result()
+			if err != nil || len(replicas) != 2 {
+				return false
+			}
+			others, err := sentinel.Sentinels(ctx, sentinelMaster).Result()
+			return err == nil && len(others) == 2
+		}, 30*time.Second, 200*time.Millisecond, "sentinel %s", addr)
+		sentinel.Close()
+	}
+
+	return rt, sentinels
+}
+
+// TestRedisSentinel demonstrates Redis Sentinel and the failover of the primary
+func TestRedisSentinel(t *testing.T) {
+	// Not parallel: the failover timeouts assume the machine is not busy
+	// starting the containers of other tests
+	ctx := context.Background()
+	rt, sentinels := startRedisSentinel(t)
+
+	// The failover client asks the sentinels for the primary
+	client := redis.NewFailoverClient(&redis.FailoverOptions{
+		MasterName:    sentinelMaster,
+		SentinelAddrs: sentinels,
+		Dialer:        rt.remap,
+	})
+	defer client.Close()
+
+	sentinel := redis.NewSentinelClient(&redis.Options{Addr: sentinels[0]})
+	defer sentinel.Close()
+
+	primaryAddr := func() (string, error) {
+		addr, err := sentinel.GetMasterAddrByName(ctx, sentinelMaster).Result()
+		if err != nil {
+			return "", err
+		}
+		return net.JoinHostPort(addr[0], addr[1]), nil
+	}
+
+	t.Run("Replication", func(t *testing.T) {
+		require.NoError(t, client.Set(ctx, "greeting", "Hello, Sentinel!", 0).Err())
+
+		replicas, err := client.Wait(ctx, 2, 5*time.Second).Result()
+		require.NoError(t, err)
+		require.Equal(t, int64(2), replicas)
+	})
+
+	t.Run("Failover", func(t *testing.T) {
+		addr, err := primaryAddr()
+		require.NoError(t, err)
+		require.NoError(t, rt.nodes[addr].Stop(ctx, nil))
+		t.Logf("Stopped primary %s", addr)
+
+		// The sentinels promote a replica, and the client follows
+		var promoted string
+		require.EventuallyWithT(t, func(c *assert.CollectT) {
+			promoted, err = primaryAddr()
+			if !assert.NoError(c, err) || !assert.NotEqual(c, addr, promoted) {
+				return
+			}
+
+			val, err := client.Get(ctx, "greeting").Result()
+			if assert.NoError(c, err) && assert.Equal(c, "Hello, Sentinel!", val) {
+				assert.NoError(c, client.Set(ctx, "greeting", "Hello again!", 0).Err())
+			}
+		}, 30*time.Second, 500*time.Millisecond)
+
+		t.Logf("Replica %s was promoted", promoted)
+	})
+}
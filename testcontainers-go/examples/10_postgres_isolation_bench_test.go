@@ -0,0 +1,213 @@
+package examples_test
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/url"
+	"testing"
+
+	_ "github.com/lib/pq"
+	"github.com/stretchr/testify/require"
+	"github.com/testcontainers/testcontainers-go"
+	"github.com/testcontainers/testcontainers-go/modules/postgres"
+
+	"github.com/testcontainers/testcontainers-go/examples/internal/images"
+)
+
+// The benchmarks below compare four ways of giving every test the same
+// initial database. Each iteration is one "test": it gets a database in the
+// initial state, checks it, and modifies it.
+//
+// Run them with:
+//
+//	go test -run '^$' -bench BenchmarkIsolation -benchtime 20x
+//
+// Besides ns/op, each benchmark reports containers/op, the number of
+// containers started per test.
+
+// isolationSchema is the initial state every test expects
+const isolationSchema = `
+	CREATE TABLE products (
+		id SERIAL PRIMARY KEY,
+		name TEXT NOT NULL,
+		price DECIMAL(10, 2) NOT NULL
+	);
+	INSERT INTO products (name, price)
+	SELECT 'product ' || i, i FROM generate_series(1, 100) AS i;
+`
+
+// querier is implemented by *sql.DB and *sql.Tx
+type querier interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+// isolatedTest is the body of a test: it fails if it does not start from
+// the initial state, and leaves the database modified
+func isolatedTest(b *testing.B, db querier) {
+	b.Helper()
+	ctx := context.Background()
+
+	var count int
+	require.NoError(b, db.QueryRowContext(ctx, `SELECT COUNT(*) FROM products`).Scan(&count))
+	require.Equal(b, 100, count, "the previous test leaked into this one")
+
+	for i := range 10 {
+		_, err := db.ExecContext(ctx, `INSERT INTO products (name, price) VALUES ($1, $2)`, fmt.Sprintf("new %d", i), 1)
+		require.NoError(b, err)
+	}
+	_, err := db.ExecContext(ctx, `UPDATE products SET price = price * 2`)
+	require.NoError(b, err)
+	_, err = db.ExecContext(ctx, `DELETE FROM products WHERE id <= 10`)
+	require.NoError(b, err)
+}
+
+// startIsolationPostgres starts a container holding the initial state in
+// the database "isolation", and returns it with its connection string
+func startIsolationPostgres(b *testing.B) (*postgres.PostgresContainer, string) {
+	b.Helper()
+	ctx := context.Background()
+
+	pgContainer, err := postgres.Run(
+		ctx,
+		images.Postgres.Ref(),
+		// Snapshots cannot be taken of the default 'postgres' database
+		postgres.WithDatabase("isolation"),
+		postgres.BasicWaitStrategies(),
+		recordStartup(b),
+	)
+	testcontainers.CleanupContainer(b, pgContainer)
+	require.NoError(b, err)
+
+	connStr, err := pgContainer.ConnectionString(ctx, "sslmode=disable")
+	require.NoError(b, err)
+
+	db, err := sql.Open("postgres", connStr)
+	require.NoError(b, err)
+	defer db.Close()
+
+	_, err = db.Exec(isolationSchema)
+	require.NoError(b, err)
+
+	return pgContainer, connStr
+}
+
+// openDB opens a connection pool and fails the benchmark if it cannot
+func openDB(b *testing.B, connStr string) *sql.DB {
+	b.Helper()
+
+	db, err := sql.Open("postgres", connStr)
+	require.NoError(b, err)
+	return db
+}
+
+// withDatabase returns connStr with its database replaced by name
+func withDatabase(b *testing.B, connStr string, name string) string {
+	b.Helper()
+
+	u, err := url.Parse(connStr)
+	require.NoError(b, err)
+	u.Path = "/" + name
+	return u.String()
+}
+
+// BenchmarkIsolationFreshContainer starts a new container for every test:
+// the strongest isolation, and the most expensive
+func BenchmarkIsolationFreshContainer(b *testing.B) {
+	var containers int
+	for b.Loop() {
+		func() {
+			pgContainer, connStr := startIsolationPostgres(b)
+			containers++
+			// Terminate now rather than when the benchmark ends, as a test would
+			defer func() {
+				require.NoError(b, testcontainers.TerminateContainer(pgContainer))
+			}()
+
+			db := openDB(b, connStr)
+			defer db.Close()
+
+			isolatedTest(b, db)
+		}()
+	}
+
+	b.ReportMetric(float64(containers)/float64(b.N), "containers/op")
+}
+
+// BenchmarkIsolationSnapshot restores a snapshot after every test, as in
+// TestPostgresMultipleSnapshots
+func BenchmarkIsolationSnapshot(b *testing.B) {
+	ctx := context.Background()
+	pgContainer, connStr := startIsolationPostgres(b)
+
+	require.NoError(b, pgContainer.Snapshot(ctx, postgres.WithSnapshotName("initial")))
+
+	for b.Loop() {
+		db := openDB(b, connStr)
+		isolatedTest(b, db)
+
+		// PostgreSQL can't restore a database with active connections
+		require.NoError(b, db.Close())
+		require.NoError(b, pgContainer.Restore(ctx, postgres.WithSnapshotName("initial")))
+	}
+
+	b.ReportMetric(1/float64(b.N), "containers/op")
+}
+
+// BenchmarkIsolationRollback runs every test in a transaction that is rolled
+// back. Code under test committing its own transactions cannot be tested
+// this way.
+func BenchmarkIsolationRollback(b *testing.B) {
+	ctx := context.Background()
+	_, connStr := startIsolationPostgres(b)
+
+	db := openDB(b, connStr)
+	defer db.Close()
+
+	for b.Loop() {
+		tx, err := db.BeginTx(ctx, nil)
+		require.NoError(b, err)
+
+		isolatedTest(b, tx)
+
+		require.NoError(b, tx.Rollback())
+	}
+
+	b.ReportMetric(1/float64(b.N), "containers/op")
+}
+
+// BenchmarkIsolationTemplate clones the initial database for every test
+// with CREATE DATABASE ... TEMPLATE, and drops the clone afterwards. Restore
+// uses the same mechanism, but this way tests never wait for each other and
+// could run in parallel.
+func BenchmarkIsolationTemplate(b *testing.B) {
+	ctx := context.Background()
+	_, connStr := startIsolationPostgres(b)
+
+	// The clones are created from a connection to another database, since
+	// the template must have no connections
+	admin := openDB(b, withDatabase(b, connStr, "postgres"))
+	defer admin.Close()
+
+	_, err := admin.ExecContext(ctx, `ALTER DATABASE isolation WITH IS_TEMPLATE true`)
+	require.NoError(b, err)
+
+	i := 0
+	for b.Loop() {
+		name := fmt.Sprintf("isolation_%d", i)
+		i++
+
+		_, err := admin.ExecContext(ctx, fmt.Sprintf(`CREATE DATABASE %s TEMPLATE isolation`, name))
+		require.NoError(b, err)
+
+		db := openDB(b, withDatabase(b, connStr, name))
+		isolatedTest(b, db)
+		require.NoError(b, db.Close())
+
+		_, err = admin.ExecContext(ctx, fmt.Sprintf(`DROP DATABASE %s`, name))
+		require.NoError(b, err)
+	}
+
+	b.ReportMetric(1/float64(b.N), "containers/op")
+}
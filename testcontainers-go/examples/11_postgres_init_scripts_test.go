@@ -0,0 +1,232 @@
+package examples_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/url"
+	"os"
+	"testing"
+
+	"github.com/golang-migrate/migrate/v4"
+	_ "github.com/golang-migrate/migrate/v4/database/postgres"
+	"github.com/golang-migrate/migrate/v4/source/iofs"
+	_ "github.com/lib/pq"
+	"github.com/stretchr/testify/require"
+	"github.com/testcontainers/testcontainers-go"
+	"github.com/testcontainers/testcontainers-go/modules/postgres"
+
+	"github.com/testcontainers/testcontainers-go/examples/internal/images"
+)
+
+// TestPostgresInitScripts demonstrates bootstrapping a database with init scripts
+func TestPostgresInitScripts(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	// The scripts are copied to /docker-entrypoint-initdb.d, where the image
+	// runs them in alphabetical order the first time it starts: the number
+	// prefixes make the order explicit
+	pgContainer, err := postgres.Run(
+		ctx,
+		images.Postgres.Ref(),
+		postgres.WithDatabase("shop"),
+		postgres.WithInitScripts(
+			"testdata/init/01_schema.sql",
+			"testdata/init/02_seed.sql",
+		),
+		postgres.BasicWaitStrategies(),
+		recordStartup(t),
+	)
+	testcontainers.CleanupContainer(t, pgContainer)
+	require.NoError(t, err)
+
+	connStr, err := pgContainer.ConnectionString(ctx, "sslmode=disable")
+	require.NoError(t, err)
+
+	db, err := sql.Open("postgres", connStr)
+	require.NoError(t, err)
+	defer db.Close()
+
+	var customers, orders int
+	err = db.QueryRow(`SELECT (SELECT COUNT(*) FROM customers), (SELECT COUNT(*) FROM orders)`).Scan(&customers, &orders)
+	require.NoError(t, err)
+	require.Equal(t, 2, customers)
+	require.Equal(t, 3, orders)
+
+	t.Log("Schema and seed data were created by init scripts")
+}
+
+// TestPostgresOrderedInitScripts demonstrates running init scripts in the order they are given
+func TestPostgresOrderedInitScripts(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	// readonly_role.sql sorts before reporting.sql but needs its view.
+	// WithOrderedInitScripts prefixes the files with their position, so they
+	// run in the order given whatever their names.
+	pgContainer, err := postgres.Run(
+		ctx,
+		images.Postgres.Ref(),
+		postgres.WithDatabase("shop"),
+		postgres.WithOrderedInitScripts(
+			"testdata/init/01_schema.sql",
+			"testdata/init/02_seed.sql",
+			"testdata/init/reporting.sql",
+			"testdata/init/readonly_role.sql",
+		),
+		postgres.BasicWaitStrategies(),
+		recordStartup(t),
+	)
+	testcontainers.CleanupContainer(t, pgContainer)
+	require.NoError(t, err)
+
+	// Connect as the role created by the last script
+	connStr, err := pgContainer.ConnectionString(ctx, "sslmode=disable")
+	require.NoError(t, err)
+
+	db, err := sql.Open("postgres", withCredentials(t, connStr, "reporter", "reporter"))
+	require.NoError(t, err)
+	defer db.Close()
+
+	var name string
+	var count int
+	var total float64
+	err = db.QueryRow(`SELECT name, orders, total FROM customer_totals ORDER BY total DESC LIMIT 1`).Scan(&name, &count, &total)
+	require.NoError(t, err)
+	require.Equal(t, "Alice", name)
+	require.Equal(t, 2, count)
+	require.InDelta(t, 35.50, total, 0.001)
+
+	// The role can only read the view
+	_, err = db.Exec(`DELETE FROM orders`)
+	require.ErrorContains(t, err, "permission denied")
+
+	t.Log("Init scripts ran in the order they were given")
+}
+
+// golangMigrateHook applies the golang-migrate migrations of fsys once the
+// container is ready, so that tests start with an up-to-date schema
+func golangMigrateHook(fsys fs.FS, user, password, database string) testcontainers.ContainerLifecycleHooks {
+	return testcontainers.ContainerLifecycleHooks{
+		PostReadies: []testcontainers.ContainerHook{
+			func(ctx context.Context, ctr testcontainers.Container) error {
+				endpoint, err := ctr.PortEndpoint(ctx, "5432/tcp", "")
+				if err != nil {
+					return fmt.Errorf("endpoint: %w", err)
+				}
+
+				m, err := newMigrate(fsys, fmt.Sprintf("postgres://%s:%s@%s/%s?sslmode=disable", user, password, endpoint, database))
+				if err != nil {
+					return err
+				}
+				defer m.Close()
+
+				// ErrNoChange is not a failure: the schema is up to date
+				if err := m.Up(); err != nil && !errors.Is(err, migrate.ErrNoChange) {
+					return fmt.Errorf("migrate up: %w", err)
+				}
+				return nil
+			},
+		},
+	}
+}
+
+// newMigrate returns a golang-migrate instance applying the migrations of
+// fsys to the database at dsn
+func newMigrate(fsys fs.FS, dsn string) (*migrate.Migrate, error) {
+	src, err := iofs.New(fsys, ".")
+	if err != nil {
+		return nil, fmt.Errorf("migrations source: %w", err)
+	}
+
+	m, err := migrate.NewWithSourceInstance("iofs", src, dsn)
+	if err != nil {
+		return nil, fmt.Errorf("migrate: %w", err)
+	}
+	return m, nil
+}
+
+// TestPostgresGolangMigrate demonstrates applying golang-migrate migrations from a lifecycle hook
+func TestPostgresGolangMigrate(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	migrations := os.DirFS("testdata/migrate")
+
+	pgContainer, err := postgres.Run(
+		ctx,
+		images.Postgres.Ref(),
+		postgres.WithDatabase("bank"),
+		postgres.WithUsername("bank"),
+		postgres.WithPassword("bank"),
+		postgres.BasicWaitStrategies(),
+		testcontainers.WithAdditionalLifecycleHooks(
+			golangMigrateHook(migrations, "bank", "bank", "bank"),
+		),
+		recordStartup(t),
+	)
+	testcontainers.CleanupContainer(t, pgContainer)
+	require.NoError(t, err)
+
+	connStr, err := pgContainer.ConnectionString(ctx, "sslmode=disable")
+	require.NoError(t, err)
+
+	m, err := newMigrate(migrations, connStr)
+	require.NoError(t, err)
+	defer m.Close()
+
+	db, err := sql.Open("postgres", connStr)
+	require.NoError(t, err)
+	defer db.Close()
+
+	t.Run("Version", func(t *testing.T) {
+		version, dirty, err := m.Version()
+		require.NoError(t, err)
+		require.Equal(t, uint(2), version)
+		require.False(t, dirty)
+
+		// golang-migrate records the version in schema_migrations
+		var recorded int
+		err = db.QueryRow(`SELECT version FROM schema_migrations`).Scan(&recorded)
+		require.NoError(t, err)
+		require.Equal(t, 2, recorded)
+
+		_, err = db.Exec(`INSERT INTO accounts (owner, balance) VALUES ('Alice', 100)`)
+		require.NoError(t, err)
+	})
+
+	t.Run("Down", func(t *testing.T) {
+		// Roll back the last migration, then apply it again
+		require.NoError(t, m.Steps(-1))
+
+		version, _, err := m.Version()
+		require.NoError(t, err)
+		require.Equal(t, uint(1), version)
+
+		_, err = db.Exec(`SELECT balance FROM accounts`)
+		require.ErrorContains(t, err, `column "balance" does not exist`)
+
+		require.NoError(t, m.Up())
+
+		version, _, err = m.Version()
+		require.NoError(t, err)
+		require.Equal(t, uint(2), version)
+	})
+
+	t.Log("Migrations were applied by golang-migrate from a PostReadies hook")
+}
+
+// withCredentials returns connStr with its user and password replaced
+func withCredentials(t *testing.T, connStr, user, password string) string {
+	t.Helper()
+
+	u, err := url.Parse(connStr)
+	require.NoError(t, err)
+	u.User = url.UserPassword(user, password)
+	return u.String()
+}
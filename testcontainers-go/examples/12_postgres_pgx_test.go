@@ -0,0 +1,286 @@
+package examples_test
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	_ "github.com/jackc/pgx/v5/stdlib"
+	_ "github.com/lib/pq"
+	"github.com/stretchr/testify/require"
+	"github.com/testcontainers/testcontainers-go"
+	"github.com/testcontainers/testcontainers-go/modules/postgres"
+
+	"github.com/testcontainers/testcontainers-go/examples/internal/images"
+)
+
+// startPgxPostgres starts a container and returns its connection string,
+// which lib/pq and pgx both accept
+func startPgxPostgres(t *testing.T) string {
+	t.Helper()
+	ctx := context.Background()
+
+	pgContainer, err := postgres.Run(
+		ctx,
+		images.Postgres.Ref(),
+		postgres.WithDatabase("testdb"),
+		postgres.BasicWaitStrategies(),
+		recordStartup(t),
+	)
+	testcontainers.CleanupContainer(t, pgContainer)
+	require.NoError(t, err)
+
+	connStr, err := pgContainer.ConnectionString(ctx, "sslmode=disable")
+	require.NoError(t, err)
+	return connStr
+}
+
+// TestPostgresPgx demonstrates connecting with pgx, natively and through database/sql
+func TestPostgresPgx(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	connStr := startPgxPostgres(t)
+
+	t.Run("Native", func(t *testing.T) {
+		conn, err := pgx.Connect(ctx, connStr)
+		require.NoError(t, err)
+		defer conn.Close(ctx)
+
+		// pgx maps PostgreSQL types to Go types, arrays included
+		var tags []string
+		var created time.Time
+		err = conn.QueryRow(ctx, `SELECT ARRAY['a', 'b'], $1::timestamptz`, time.Unix(0, 0)).Scan(&tags, &created)
+		require.NoError(t, err)
+		require.Equal(t, []string{"a", "b"}, tags)
+		require.True(t, created.Equal(time.Unix(0, 0)))
+	})
+
+	t.Run("DatabaseSQL", func(t *testing.T) {
+		// The same connection string works with both database/sql drivers:
+		// lib/pq registers "postgres", pgx/v5/stdlib registers "pgx"
+		for _, driver := range []string{"postgres", "pgx"} {
+			db, err := sql.Open(driver, connStr)
+			require.NoError(t, err)
+			defer db.Close()
+
+			var version string
+			require.NoError(t, db.QueryRowContext(ctx, `SHOW server_version`).Scan(&version))
+			t.Logf("%s driver connected to PostgreSQL %s", driver, version)
+		}
+	})
+}
+
+// TestPostgresPgxListenNotify demonstrates receiving notifications with LISTEN/NOTIFY
+func TestPostgresPgxListenNotify(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	connStr := startPgxPostgres(t)
+
+	// Notifications are delivered to a connection, so the listener needs
+	// one of its own rather than one from a pool
+	listener, err := pgx.Connect(ctx, connStr)
+	require.NoError(t, err)
+	defer listener.Close(ctx)
+
+	_, err = listener.Exec(ctx, `LISTEN events`)
+	require.NoError(t, err)
+
+	notifier, err := pgx.Connect(ctx, connStr)
+	require.NoError(t, err)
+	defer notifier.Close(ctx)
+
+	// waitForNotification returns the payload of the next notification, or
+	// an error if none arrives within timeout
+	waitForNotification := func(t *testing.T, timeout time.Duration) (string, error) {
+		t.Helper()
+
+		ctx, cancel := context.WithTimeout(ctx, timeout)
+		defer cancel()
+
+		n, err := listener.WaitForNotification(ctx)
+		if err != nil {
+			return "", err
+		}
+		require.Equal(t, "events", n.Channel)
+		return n.Payload, nil
+	}
+
+	t.Run("Notify", func(t *testing.T) {
+		_, err := notifier.Exec(ctx, `SELECT pg_notify('events', $1)`, "user created")
+		require.NoError(t, err)
+
+		payload, err := waitForNotification(t, 5*time.Second)
+		require.NoError(t, err)
+		require.Equal(t, "user created", payload)
+	})
+
+	t.Run("Transaction", func(t *testing.T) {
+		tx, err := notifier.Begin(ctx)
+		require.NoError(t, err)
+		defer tx.Rollback(ctx)
+
+		_, err = tx.Exec(ctx, `SELECT pg_notify('events', 'order paid')`)
+		require.NoError(t, err)
+
+		// Notifications are only sent when the transaction commits. A
+		// timeout leaves the listener usable.
+		_, err = waitForNotification(t, 500*time.Millisecond)
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+
+		require.NoError(t, tx.Commit(ctx))
+
+		payload, err := waitForNotification(t, 5*time.Second)
+		require.NoError(t, err)
+		require.Equal(t, "order paid", payload)
+	})
+
+	t.Log("Notifications were received with LISTEN/NOTIFY")
+}
+
+// TestPostgresPgxCopyFrom demonstrates bulk loading rows with COPY FROM
+func TestPostgresPgxCopyFrom(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	connStr := startPgxPostgres(t)
+
+	conn, err := pgx.Connect(ctx, connStr)
+	require.NoError(t, err)
+	defer conn.Close(ctx)
+
+	_, err = conn.Exec(ctx, `
+		CREATE TABLE events (
+			id INTEGER PRIMARY KEY,
+			name TEXT NOT NULL,
+			created_at TIMESTAMPTZ NOT NULL
+		)
+	`)
+	require.NoError(t, err)
+
+	columns := []string{"id", "name", "created_at"}
+
+	t.Run("Rows", func(t *testing.T) {
+		rows := [][]any{
+			{1, "signup", time.Now()},
+			{2, "login", time.Now()},
+		}
+
+		copied, err := conn.CopyFrom(ctx, pgx.Identifier{"events"}, columns, pgx.CopyFromRows(rows))
+		require.NoError(t, err)
+		require.Equal(t, int64(2), copied)
+	})
+
+	t.Run("Slice", func(t *testing.T) {
+		// CopyFromSlice builds the rows as they are sent, so large loads
+		// need not be held in memory
+		const n = 10000
+		start := time.Now()
+
+		copied, err := conn.CopyFrom(ctx, pgx.Identifier{"events"}, columns, pgx.CopyFromSlice(n, func(i int) ([]any, error) {
+			return []any{i + 3, fmt.Sprintf("event %d", i), start}, nil
+		}))
+		require.NoError(t, err)
+		require.Equal(t, int64(n), copied)
+		t.Logf("Copied %d rows in %s", n, time.Since(start))
+	})
+
+	t.Run("Conflict", func(t *testing.T) {
+		// COPY is all or nothing: a duplicate key loads none of the rows
+		rows := [][]any{
+			{20000, "new", time.Now()},
+			{1, "duplicate", time.Now()},
+		}
+
+		_, err := conn.CopyFrom(ctx, pgx.Identifier{"events"}, columns, pgx.CopyFromRows(rows))
+		require.ErrorContains(t, err, "duplicate key")
+
+		var count int
+		require.NoError(t, conn.QueryRow(ctx, `SELECT COUNT(*) FROM events`).Scan(&count))
+		require.Equal(t, 10002, count)
+	})
+}
+
+// TestPostgresPgxPoolExhaustion demonstrates what happens when every connection of a pool is in use
+func TestPostgresPgxPoolExhaustion(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	connStr := startPgxPostgres(t)
+
+	cfg, err := pgxpool.ParseConfig(connStr)
+	require.NoError(t, err)
+	cfg.MaxConns = 2
+
+	pool, err := pgxpool.NewWithConfig(ctx, cfg)
+	require.NoError(t, err)
+	defer pool.Close()
+
+	// Hold every connection of the pool
+	first, err := pool.Acquire(ctx)
+	require.NoError(t, err)
+	second, err := pool.Acquire(ctx)
+	require.NoError(t, err)
+	require.Equal(t, int32(2), pool.Stat().AcquiredConns())
+
+	t.Run("Timeout", func(t *testing.T) {
+		// Acquire blocks until a connection is released: only the context
+		// bounds the wait
+		acquireCtx, cancel := context.WithTimeout(ctx, 500*time.Millisecond)
+		defer cancel()
+
+		_, err := pool.Acquire(acquireCtx)
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+
+		// Queries through the pool acquire a connection too
+		execCtx, cancel := context.WithTimeout(ctx, 500*time.Millisecond)
+		defer cancel()
+
+		_, err = pool.Exec(execCtx, `SELECT 1`)
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+
+		require.Equal(t, int64(2), pool.Stat().CanceledAcquireCount())
+	})
+
+	t.Run("Release", func(t *testing.T) {
+		waited := pool.Stat().EmptyAcquireCount()
+		started := make(chan struct{})
+		acquired := make(chan error, 1)
+		go func() {
+			ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+			defer cancel()
+
+			close(started)
+			conn, err := pool.Acquire(ctx)
+			if err == nil {
+				conn.Release()
+			}
+			acquired <- err
+		}()
+
+		// The Acquire waits as long as both connections are held
+		<-started
+		require.Never(t, func() bool {
+			return len(acquired) > 0
+		}, 200*time.Millisecond, 10*time.Millisecond)
+
+		// and gets the connection as soon as one is released
+		first.Release()
+		require.NoError(t, <-acquired)
+
+		// The pool counts the Acquire calls that had to wait once they succeed
+		require.Greater(t, pool.Stat().EmptyAcquireCount(), waited)
+	})
+
+	second.Release()
+
+	stat := pool.Stat()
+	require.Equal(t, int32(0), stat.AcquiredConns())
+	require.LessOrEqual(t, stat.TotalConns(), int32(2))
+	t.Logf("Acquires: %d, waited: %d, canceled: %d", stat.AcquireCount(), stat.EmptyAcquireCount(), stat.CanceledAcquireCount())
+}
@@ -0,0 +1,166 @@
+package examples_test
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	_ "github.com/lib/pq"
+	"github.com/stretchr/testify/require"
+	"github.com/testcontainers/testcontainers-go"
+	"github.com/testcontainers/testcontainers-go/modules/postgres"
+
+	"github.com/testcontainers/testcontainers-go/examples/internal/certs"
+	"github.com/testcontainers/testcontainers-go/examples/internal/images"
+)
+
+// pgTLSDir is where the certificates are copied, as set in
+// testdata/postgres-tls/postgresql.conf
+const pgTLSDir = "/var/lib/postgresql/tls"
+
+// dockerHost returns the host the mapped ports are reached on, which the
+// server certificate must name for clients to verify it
+func dockerHost(t *testing.T) string {
+	t.Helper()
+
+	provider, err := testcontainers.NewDockerProvider()
+	require.NoError(t, err)
+	defer provider.Close()
+
+	host, err := provider.DaemonHost(context.Background())
+	require.NoError(t, err)
+	return host
+}
+
+// writeTemp writes data to a file of the test's temporary directory, and
+// returns its path
+func writeTemp(t *testing.T, name string, data []byte) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(path, data, 0o600))
+	return path
+}
+
+// TestPostgresTLS demonstrates connecting to PostgreSQL over TLS with sslmode=verify-full
+func TestPostgresTLS(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	// A throwaway CA issues the server certificate for the host the tests
+	// connect to
+	ca, err := certs.NewCA("examples CA")
+	require.NoError(t, err)
+	server, err := ca.Issue("postgres", dockerHost(t))
+	require.NoError(t, err)
+
+	pgContainer, err := postgres.Run(
+		ctx,
+		images.Postgres.Ref(),
+		postgres.WithDatabase("secure"),
+		postgres.WithConfigFile("testdata/postgres-tls/postgresql.conf"),
+		testcontainers.WithFiles(
+			testcontainers.ContainerFile{
+				HostFilePath:      "testdata/postgres-tls/pg_hba.conf",
+				ContainerFilePath: "/etc/postgresql/pg_hba.conf",
+				FileMode:          0o644,
+			},
+			testcontainers.ContainerFile{
+				Reader:            bytes.NewReader(ca.CertPEM),
+				ContainerFilePath: pgTLSDir + "/ca.crt",
+				FileMode:          0o644,
+			},
+			testcontainers.ContainerFile{
+				Reader:            bytes.NewReader(server.CertPEM),
+				ContainerFilePath: pgTLSDir + "/server.crt",
+				FileMode:          0o644,
+			},
+			testcontainers.ContainerFile{
+				Reader:            bytes.NewReader(server.KeyPEM),
+				ContainerFilePath: pgTLSDir + "/server.key",
+				FileMode:          0o600,
+			},
+		),
+		// Files are copied as root, but PostgreSQL only reads a key owned
+		// by the user it runs as
+		testcontainers.WithEntrypoint("sh", "-c",
+			`chown -R postgres:postgres `+pgTLSDir+` && exec docker-entrypoint.sh "$@"`, "--",
+		),
+		postgres.BasicWaitStrategies(),
+		recordStartup(t),
+	)
+	testcontainers.CleanupContainer(t, pgContainer)
+	require.NoError(t, err)
+
+	rootCert := writeTemp(t, "ca.crt", ca.CertPEM)
+
+	t.Run("VerifyFull", func(t *testing.T) {
+		// verify-full checks that the server certificate is signed by the
+		// CA and names the host
+		connStr, err := pgContainer.ConnectionString(ctx, "sslmode=verify-full", "sslrootcert="+rootCert)
+		require.NoError(t, err)
+
+		db, err := sql.Open("postgres", connStr)
+		require.NoError(t, err)
+		defer db.Close()
+
+		var ssl bool
+		var version string
+		err = db.QueryRow(`SELECT ssl, version FROM pg_stat_ssl WHERE pid = pg_backend_pid()`).Scan(&ssl, &version)
+		require.NoError(t, err)
+		require.True(t, ssl)
+		t.Logf("Connected with %s", version)
+	})
+
+	t.Run("Pgx", func(t *testing.T) {
+		connStr, err := pgContainer.ConnectionString(ctx, "sslmode=verify-full")
+		require.NoError(t, err)
+
+		// Code building its tls.Config rather than reading files can trust
+		// the CA directly
+		cfg, err := pgx.ParseConfig(connStr)
+		require.NoError(t, err)
+		cfg.TLSConfig.RootCAs = ca.Pool()
+
+		conn, err := pgx.ConnectConfig(ctx, cfg)
+		require.NoError(t, err)
+		defer conn.Close(ctx)
+
+		var ssl bool
+		require.NoError(t, conn.QueryRow(ctx, `SELECT ssl FROM pg_stat_ssl WHERE pid = pg_backend_pid()`).Scan(&ssl))
+		require.True(t, ssl)
+	})
+
+	t.Run("Plaintext", func(t *testing.T) {
+		// pg_hba.conf only accepts TLS connections
+		connStr, err := pgContainer.ConnectionString(ctx, "sslmode=disable")
+		require.NoError(t, err)
+
+		db, err := sql.Open("postgres", connStr)
+		require.NoError(t, err)
+		defer db.Close()
+
+		require.ErrorContains(t, db.Ping(), "no pg_hba.conf entry")
+	})
+
+	t.Run("UnknownCA", func(t *testing.T) {
+		other, err := certs.NewCA("other CA")
+		require.NoError(t, err)
+
+		connStr, err := pgContainer.ConnectionString(ctx, "sslmode=verify-full", "sslrootcert="+writeTemp(t, "other.crt", other.CertPEM))
+		require.NoError(t, err)
+
+		db, err := sql.Open("postgres", connStr)
+		require.NoError(t, err)
+		defer db.Close()
+
+		require.ErrorContains(t, db.Ping(), "certificate signed by unknown authority")
+	})
+
+	t.Log("PostgreSQL only accepted connections verifying its certificate")
+}
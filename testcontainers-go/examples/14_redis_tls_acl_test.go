@@ -0,0 +1,151 @@
+package examples_test
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"fmt"
+	"testing"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/require"
+	"github.com/testcontainers/testcontainers-go"
+	tcredis "github.com/testcontainers/testcontainers-go/modules/redis"
+
+	"github.com/testcontainers/testcontainers-go/examples/internal/certs"
+	"github.com/testcontainers/testcontainers-go/examples/internal/images"
+)
+
+// connectRedisTLS returns a client authenticating as user, over TLS with
+// tlsConfig
+func connectRedisTLS(t *testing.T, endpoint, user, password string, tlsConfig *tls.Config) *redis.Client {
+	t.Helper()
+
+	// rediss:// is what a production configuration would hold: go-redis
+	// turns it into a tls.Config verifying the host, to which the CA and
+	// the client certificate are added
+	opt, err := redis.ParseURL(fmt.Sprintf("rediss://%s:%s@%s", user, password, endpoint))
+	require.NoError(t, err)
+	opt.TLSConfig.RootCAs = tlsConfig.RootCAs
+	opt.TLSConfig.Certificates = tlsConfig.Certificates
+
+	client := redis.NewClient(opt)
+	t.Cleanup(func() { client.Close() })
+	return client
+}
+
+// TestRedisTLSACL demonstrates a Redis server only accepting authenticated TLS clients
+func TestRedisTLSACL(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	ca, err := certs.NewCA("examples CA")
+	require.NoError(t, err)
+	server, err := ca.Issue("redis", dockerHost(t))
+	require.NoError(t, err)
+	clientPair, err := ca.Issue("app")
+	require.NoError(t, err)
+
+	// The ACL users and the TLS settings are in the configuration file. The
+	// key is readable by all as Redis runs as the redis user, and the files
+	// are copied as root.
+	redisContainer, err := tcredis.Run(
+		ctx,
+		images.Redis.Ref(),
+		tcredis.WithConfigFile("testdata/redis/redis-tls-acl.conf"),
+		testcontainers.WithFiles(
+			testcontainers.ContainerFile{
+				Reader:            bytes.NewReader(ca.CertPEM),
+				ContainerFilePath: "/tls/ca.crt",
+				FileMode:          0o644,
+			},
+			testcontainers.ContainerFile{
+				Reader:            bytes.NewReader(server.CertPEM),
+				ContainerFilePath: "/tls/server.crt",
+				FileMode:          0o644,
+			},
+			testcontainers.ContainerFile{
+				Reader:            bytes.NewReader(server.KeyPEM),
+				ContainerFilePath: "/tls/server.key",
+				FileMode:          0o644,
+			},
+		),
+		recordStartup(t),
+	)
+	testcontainers.CleanupContainer(t, redisContainer)
+	require.NoError(t, err)
+
+	// ConnectionString says redis:// as the module did not enable TLS
+	// itself, so the clients are built from the endpoint
+	endpoint, err := redisContainer.PortEndpoint(ctx, "6379/tcp", "")
+	require.NoError(t, err)
+
+	clientCert, err := clientPair.TLSCertificate()
+	require.NoError(t, err)
+	tlsConfig := &tls.Config{
+		RootCAs:      ca.Pool(),
+		Certificates: []tls.Certificate{clientCert},
+	}
+
+	admin := connectRedisTLS(t, endpoint, "admin", "admin-secret", tlsConfig)
+	app := connectRedisTLS(t, endpoint, "app", "app-secret", tlsConfig)
+	reader := connectRedisTLS(t, endpoint, "reader", "reader-secret", tlsConfig)
+
+	t.Run("Authorized", func(t *testing.T) {
+		require.NoError(t, app.Set(ctx, "app:greeting", "hello", 0).Err())
+
+		val, err := reader.Get(ctx, "app:greeting").Result()
+		require.NoError(t, err)
+		require.Equal(t, "hello", val)
+	})
+
+	t.Run("NoPerm", func(t *testing.T) {
+		// A command outside of the categories of the user
+		err := app.FlushAll(ctx).Err()
+		require.ErrorContains(t, err, "NOPERM")
+
+		// A key outside of its patterns
+		err = app.Set(ctx, "other:greeting", "hello", 0).Err()
+		require.ErrorContains(t, err, "NOPERM")
+
+		// A write by a read-only user
+		err = reader.Set(ctx, "app:greeting", "bye", 0).Err()
+		require.ErrorContains(t, err, "NOPERM")
+
+		// ACL DRYRUN checks the permissions of a user without running the
+		// command
+		result, err := admin.ACLDryRun(ctx, "reader", "SET", "app:greeting", "bye").Result()
+		require.NoError(t, err)
+		require.Contains(t, result, "no permissions to run the 'set' command")
+
+		val, err := admin.Get(ctx, "app:greeting").Result()
+		require.NoError(t, err)
+		require.Equal(t, "hello", val)
+	})
+
+	t.Run("Unauthenticated", func(t *testing.T) {
+		// The default user is off: connections must authenticate
+		anonymous := connectRedisTLS(t, endpoint, "", "", tlsConfig)
+		require.ErrorContains(t, anonymous.Ping(ctx).Err(), "NOAUTH")
+
+		wrong := connectRedisTLS(t, endpoint, "app", "wrong", tlsConfig)
+		require.ErrorContains(t, wrong.Ping(ctx).Err(), "WRONGPASS")
+	})
+
+	t.Run("NoClientCertificate", func(t *testing.T) {
+		// tls-auth-clients yes: the handshake fails without a certificate
+		// signed by the CA
+		noCert := connectRedisTLS(t, endpoint, "app", "app-secret", &tls.Config{RootCAs: ca.Pool()})
+		require.Error(t, noCert.Ping(ctx).Err())
+	})
+
+	t.Run("Plaintext", func(t *testing.T) {
+		plain := redis.NewClient(&redis.Options{Addr: endpoint, Username: "app", Password: "app-secret"})
+		defer plain.Close()
+
+		require.Error(t, plain.Ping(ctx).Err())
+	})
+
+	t.Log("Redis only served authenticated TLS clients within their ACL")
+}
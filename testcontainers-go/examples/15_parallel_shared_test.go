@@ -0,0 +1,145 @@
+package examples_test
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sync/atomic"
+	"testing"
+
+	_ "github.com/lib/pq"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/require"
+	"github.com/testcontainers/testcontainers-go/modules/postgres"
+	tcredis "github.com/testcontainers/testcontainers-go/modules/redis"
+
+	"github.com/testcontainers/testcontainers-go/examples/internal/images"
+	"github.com/testcontainers/testcontainers-go/examples/internal/shared"
+)
+
+// The containers below are shared by the tests of the package: the first
+// test needing one starts it, and TestMain terminates it. Each test gets a
+// Redis database or a PostgreSQL schema of its own inside, so that tests
+// running in parallel never see each other's data.
+
+var (
+	sharedRedis = shared.New(func(ctx context.Context, tb testing.TB) (*tcredis.RedisContainer, error) {
+		return tcredis.Run(ctx, images.Redis.Ref(), recordStartup(tb))
+	})
+
+	sharedPostgres = shared.New(func(ctx context.Context, tb testing.TB) (*postgres.PostgresContainer, error) {
+		return postgres.Run(ctx, images.Postgres.Ref(),
+			postgres.WithDatabase("shared"),
+			postgres.BasicWaitStrategies(),
+			recordStartup(tb),
+		)
+	})
+
+	// redisDBs are the database numbers handed out to tests. Redis has 16
+	// databases by default; database 0 is left to tests that do not share.
+	redisDBs = shared.NewSlots(1, 15)
+
+	// schemaSeq numbers the schemas, which must be unique across tests
+	schemaSeq atomic.Int64
+)
+
+// sharedRedisDB returns a client of a database of the shared Redis server
+// that no other test uses until t ends. The database is flushed before it
+// is handed to another test.
+func sharedRedisDB(t *testing.T) *redis.Client {
+	t.Helper()
+	ctx := context.Background()
+
+	connStr, err := sharedRedis.Get(t).ConnectionString(ctx)
+	require.NoError(t, err)
+
+	opt, err := redis.ParseURL(connStr)
+	require.NoError(t, err)
+	opt.DB = redisDBs.Acquire(t)
+
+	client := redis.NewClient(opt)
+	t.Cleanup(func() {
+		// Runs before the database number is released
+		require.NoError(t, client.FlushDB(ctx).Err())
+		client.Close()
+	})
+	return client
+}
+
+// sharedPostgresSchema returns a connection pool to a schema of the shared
+// PostgreSQL database created for t, and dropped when t ends. Unqualified
+// names resolve to the schema through search_path.
+func sharedPostgresSchema(t *testing.T) *sql.DB {
+	t.Helper()
+	ctx := context.Background()
+
+	connStr, err := sharedPostgres.Get(t).ConnectionString(ctx, "sslmode=disable")
+	require.NoError(t, err)
+
+	admin, err := sql.Open("postgres", connStr)
+	require.NoError(t, err)
+	t.Cleanup(func() { admin.Close() })
+
+	schema := fmt.Sprintf("test_%d", schemaSeq.Add(1))
+	_, err = admin.ExecContext(ctx, `CREATE SCHEMA `+schema)
+	require.NoError(t, err)
+
+	// lib/pq passes search_path to the server as a run-time parameter, so
+	// every connection of the pool uses the schema
+	db, err := sql.Open("postgres", connStr+"&search_path="+schema)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		db.Close()
+		_, err := admin.ExecContext(ctx, `DROP SCHEMA `+schema+` CASCADE`)
+		require.NoError(t, err)
+	})
+	return db
+}
+
+// TestParallelSharedContainers demonstrates parallel tests sharing containers, isolated from each other
+func TestParallelSharedContainers(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	// Every subtest uses the same key and table names: only the isolation
+	// keeps the counts exact. Run with -race to also check the Go side.
+	for i := range 8 {
+		t.Run(fmt.Sprintf("Worker%d", i), func(t *testing.T) {
+			t.Parallel()
+
+			t.Run("Redis", func(t *testing.T) {
+				client := sharedRedisDB(t)
+
+				for range 100 {
+					require.NoError(t, client.Incr(ctx, "counter").Err())
+				}
+
+				count, err := client.Get(ctx, "counter").Int()
+				require.NoError(t, err)
+				require.Equal(t, 100, count)
+
+				size, err := client.DBSize(ctx).Result()
+				require.NoError(t, err)
+				require.Equal(t, int64(1), size, "another test wrote to this database")
+			})
+
+			t.Run("Postgres", func(t *testing.T) {
+				db := sharedPostgresSchema(t)
+
+				_, err := db.ExecContext(ctx, `CREATE TABLE items (id SERIAL PRIMARY KEY, worker INTEGER NOT NULL)`)
+				require.NoError(t, err)
+
+				for range 10 {
+					_, err := db.ExecContext(ctx, `INSERT INTO items (worker) VALUES ($1)`, i)
+					require.NoError(t, err)
+				}
+
+				var count, workers int
+				err = db.QueryRowContext(ctx, `SELECT COUNT(*), COUNT(DISTINCT worker) FROM items`).Scan(&count, &workers)
+				require.NoError(t, err)
+				require.Equal(t, 10, count)
+				require.Equal(t, 1, workers, "another test wrote to this table")
+			})
+		})
+	}
+}
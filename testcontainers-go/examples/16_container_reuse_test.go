@@ -0,0 +1,191 @@
+package examples_test
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"testing"
+
+	_ "github.com/lib/pq"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/require"
+	"github.com/testcontainers/testcontainers-go/modules/postgres"
+	tcredis "github.com/testcontainers/testcontainers-go/modules/redis"
+
+	"github.com/testcontainers/testcontainers-go/examples/internal/images"
+	"github.com/testcontainers/testcontainers-go/examples/internal/reuse"
+)
+
+// The tests below, and the snapshot tests of 02_postgres_snapshot_test.go,
+// reuse their containers between runs when EXAMPLES_REUSE is set, and start
+// fresh ones otherwise:
+//
+//	EXAMPLES_REUSE=1 TESTCONTAINERS_RYUK_DISABLED=true go test -v -run Reuse
+//	EXAMPLES_REUSE=1 TESTCONTAINERS_RYUK_DISABLED=true go test -v -run TestPostgresSnapshot
+
+// reuseSeed is the state every test of TestPostgresReuse starts from
+const reuseSeed = `
+	CREATE TABLE products (
+		id SERIAL PRIMARY KEY,
+		name TEXT NOT NULL
+	);
+	INSERT INTO products (name) VALUES ('Widget');
+`
+
+// reuseSnapshot is the snapshot of the database right after the seed
+const reuseSnapshot = "baseline"
+
+// reusablePostgres returns a PostgreSQL container, maybe left by a previous
+// run, and its connection string. Whatever that run did, the database is
+// restored to its state right after seed before the container is handed
+// out.
+func reusablePostgres(t *testing.T, seed string) (*postgres.PostgresContainer, string) {
+	t.Helper()
+	ctx := context.Background()
+
+	// The seed is part of the name: changing it starts a new container
+	// rather than restoring the snapshot of the previous seed
+	sum := sha256.Sum256([]byte(seed))
+
+	pgContainer, err := postgres.Run(
+		ctx,
+		images.Postgres.Ref(),
+		postgres.WithDatabase("reusedb"),
+		postgres.BasicWaitStrategies(),
+		reuse.Customize(t, "postgres-"+hex.EncodeToString(sum[:6])),
+		recordStartup(t),
+	)
+	// Terminated with the test unless it is reused
+	reuse.Cleanup(t, pgContainer)
+	require.NoError(t, err)
+
+	connStr, err := pgContainer.ConnectionString(ctx, "sslmode=disable")
+	require.NoError(t, err)
+
+	baseline := postgres.WithSnapshotName(reuseSnapshot)
+
+	db, err := sql.Open("postgres", connStr)
+	require.NoError(t, err)
+	defer db.Close()
+
+	var snapshotted bool
+	err = db.QueryRowContext(ctx, `SELECT EXISTS (SELECT 1 FROM pg_database WHERE datname = $1)`, reuseSnapshot).Scan(&snapshotted)
+	require.NoError(t, err)
+
+	if snapshotted {
+		// A previous run left the container: undo its changes
+		db.Close()
+		require.NoError(t, pgContainer.Restore(ctx, baseline))
+		t.Log("Restored the baseline snapshot")
+		return pgContainer, connStr
+	}
+
+	_, err = db.ExecContext(ctx, seed)
+	require.NoError(t, err)
+
+	// PostgreSQL cannot snapshot a database with active connections
+	db.Close()
+	require.NoError(t, pgContainer.Snapshot(ctx, baseline))
+	t.Log("Took the baseline snapshot")
+	return pgContainer, connStr
+}
+
+// reusableRedis returns a Redis container, maybe left by a previous run,
+// emptied before it is handed out.
+func reusableRedis(t *testing.T) (*tcredis.RedisContainer, *redis.Options) {
+	t.Helper()
+	ctx := context.Background()
+
+	redisContainer, err := tcredis.Run(ctx, images.Redis.Ref(),
+		reuse.Customize(t, "redis"),
+		recordStartup(t),
+	)
+	reuse.Cleanup(t, redisContainer)
+	require.NoError(t, err)
+
+	connStr, err := redisContainer.ConnectionString(ctx)
+	require.NoError(t, err)
+	opt, err := redis.ParseURL(connStr)
+	require.NoError(t, err)
+
+	client := redis.NewClient(opt)
+	defer client.Close()
+
+	// FLUSHALL empties every database, not only the one of opt
+	require.NoError(t, client.FlushAll(ctx).Err())
+	size, err := client.DBSize(ctx).Result()
+	require.NoError(t, err)
+	require.Zero(t, size, "Redis is not empty after FLUSHALL")
+
+	return redisContainer, opt
+}
+
+// TestPostgresReuse demonstrates reusing a PostgreSQL container between runs, restored to a baseline snapshot
+func TestPostgresReuse(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	// Each subtest stands for a run of the test: with reuse enabled, the
+	// second one gets the container the first one changed
+	var ids []string
+	for _, run := range []string{"FirstRun", "NextRun"} {
+		t.Run(run, func(t *testing.T) {
+			pgContainer, connStr := reusablePostgres(t, reuseSeed)
+			ids = append(ids, pgContainer.GetContainerID())
+
+			db, err := sql.Open("postgres", connStr)
+			require.NoError(t, err)
+			defer db.Close()
+
+			var count int
+			require.NoError(t, db.QueryRowContext(ctx, `SELECT COUNT(*) FROM products`).Scan(&count))
+			require.Equal(t, 1, count, "the database is not at its baseline")
+
+			// Left for the next run to undo
+			_, err = db.ExecContext(ctx, `INSERT INTO products (name) VALUES ('Gadget')`)
+			require.NoError(t, err)
+		})
+	}
+
+	if reuse.Enabled() {
+		require.Len(t, ids, 2)
+		require.Equal(t, ids[0], ids[1], "the container was not reused")
+	}
+}
+
+// TestRedisReuse demonstrates reusing a Redis container between runs, emptied with FLUSHALL
+func TestRedisReuse(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	var ids []string
+	for _, run := range []string{"FirstRun", "NextRun"} {
+		t.Run(run, func(t *testing.T) {
+			redisContainer, opt := reusableRedis(t)
+			ids = append(ids, redisContainer.GetContainerID())
+
+			client := redis.NewClient(opt)
+			defer client.Close()
+
+			// SETNX only succeeds on an empty server
+			created, err := client.SetNX(ctx, "session:1", "alice", 0).Result()
+			require.NoError(t, err)
+			require.True(t, created, "a previous run left session:1")
+
+			// Left in another database for the next run to flush
+			other := *opt
+			other.DB = 1
+			otherClient := redis.NewClient(&other)
+			defer otherClient.Close()
+			require.NoError(t, otherClient.Set(ctx, "leftover", "1", 0).Err())
+		})
+	}
+
+	if reuse.Enabled() {
+		require.Len(t, ids, 2)
+		require.Equal(t, ids[0], ids[1], "the container was not reused")
+	}
+}
@@ -0,0 +1,197 @@
+// Package tcast holds the AST and type helpers shared by the testcontainers
+// analyzers.
+package tcast
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+	"path"
+	"strconv"
+	"strings"
+
+	"golang.org/x/tools/go/types/typeutil"
+)
+
+const (
+	// CorePath is the import path of the testcontainers-go core package.
+	CorePath = "github.com/testcontainers/testcontainers-go"
+
+	// modulesPrefix is the import path prefix of every testcontainers-go module.
+	modulesPrefix = CorePath + "/modules/"
+)
+
+// IsTestcontainersPath reports whether pkgPath is the core package or one of its
+// modules (e.g. modules/postgres, modules/redis).
+func IsTestcontainersPath(pkgPath string) bool {
+	return pkgPath == CorePath || strings.HasPrefix(pkgPath, modulesPrefix)
+}
+
+// RunFunc returns the function called by call if it is testcontainers.Run or
+// the Run function of a module, such as postgres.Run or redis.Run.
+func RunFunc(info *types.Info, call *ast.CallExpr) *types.Func {
+	fn, ok := typeutil.Callee(info, call).(*types.Func)
+	if !ok || fn.Pkg() == nil || fn.Name() != "Run" {
+		return nil
+	}
+	if !IsTestcontainersPath(fn.Pkg().Path()) {
+		return nil
+	}
+	return fn
+}
+
+// ContainerInterface returns the testcontainers.Container interface if pkg
+// imports the core package, directly or through a module, or nil otherwise.
+func ContainerInterface(pkg *types.Package) *types.Interface {
+	seen := make(map[*types.Package]bool)
+	var find func(p *types.Package) *types.Interface
+	find = func(p *types.Package) *types.Interface {
+		if seen[p] {
+			return nil
+		}
+		seen[p] = true
+
+		if p.Path() == CorePath {
+			obj, ok := p.Scope().Lookup("Container").(*types.TypeName)
+			if !ok {
+				return nil
+			}
+			iface, _ := obj.Type().Underlying().(*types.Interface)
+			return iface
+		}
+		for _, imp := range p.Imports() {
+			if iface := find(imp); iface != nil {
+				return iface
+			}
+		}
+		return nil
+	}
+	return find(pkg)
+}
+
+// IsCoreFunc reports whether call invokes the function name of the
+// testcontainers-go core package.
+func IsCoreFunc(info *types.Info, call *ast.CallExpr, name string) bool {
+	return IsFunc(info, call, CorePath, name)
+}
+
+// IsFunc reports whether call invokes the package-level function pkgPath.name.
+func IsFunc(info *types.Info, call *ast.CallExpr, pkgPath, name string) bool {
+	fn, ok := typeutil.Callee(info, call).(*types.Func)
+	if !ok || fn.Pkg() == nil || fn.Name() != name {
+		return false
+	}
+	sig, ok := fn.Type().(*types.Signature)
+	return ok && sig.Recv() == nil && fn.Pkg().Path() == pkgPath
+}
+
+// Uses reports whether node refers to obj anywhere in its subtree.
+func Uses(info *types.Info, node ast.Node, obj types.Object) bool {
+	found := false
+	ast.Inspect(node, func(n ast.Node) bool {
+		if found {
+			return false
+		}
+		if id, ok := n.(*ast.Ident); ok && info.Uses[id] == obj {
+			found = true
+		}
+		return !found
+	})
+	return found
+}
+
+// ImportName returns the name under which file imports pkgPath, or "" if the
+// file does not import it.
+func ImportName(file *ast.File, pkgPath string) string {
+	for _, spec := range file.Imports {
+		p, err := strconv.Unquote(spec.Path.Value)
+		if err != nil || p != pkgPath {
+			continue
+		}
+		switch {
+		case spec.Name != nil:
+			return spec.Name.Name
+		case p == CorePath:
+			return "testcontainers"
+		default:
+			return path.Base(p)
+		}
+	}
+	return ""
+}
+
+// TestingParam returns the name of the innermost *testing.T, *testing.B or
+// testing.TB parameter among the enclosing functions in stack, or "" if
+// there is none.
+func TestingParam(info *types.Info, stack []ast.Node) string {
+	for i := len(stack) - 1; i >= 0; i-- {
+		var ft *ast.FuncType
+		switch fn := stack[i].(type) {
+		case *ast.FuncDecl:
+			ft = fn.Type
+		case *ast.FuncLit:
+			ft = fn.Type
+		default:
+			continue
+		}
+
+		for _, field := range ft.Params.List {
+			if !IsTestingTB(info.TypeOf(field.Type)) {
+				continue
+			}
+			for _, name := range field.Names {
+				if name.Name != "_" {
+					return name.Name
+				}
+			}
+		}
+	}
+	return ""
+}
+
+// IsTestingTB reports whether t is *testing.T, *testing.B or testing.TB.
+func IsTestingTB(t types.Type) bool {
+	if ptr, ok := t.(*types.Pointer); ok {
+		t = ptr.Elem()
+	}
+	named, ok := t.(*types.Named)
+	if !ok || named.Obj().Pkg() == nil || named.Obj().Pkg().Path() != "testing" {
+		return false
+	}
+	switch named.Obj().Name() {
+	case "T", "B", "TB":
+		return true
+	}
+	return false
+}
+
+// Indent returns the leading tabs of the line holding pos, assuming the
+// file is gofmt-ed.
+func Indent(fset *token.FileSet, pos token.Pos) string {
+	return strings.Repeat("\t", fset.Position(pos).Column-1)
+}
+
+// LineRange returns the range covering the full lines of node, including the
+// trailing newline, so that deleting it leaves no blank line behind.
+func LineRange(fset *token.FileSet, node ast.Node) (token.Pos, token.Pos) {
+	file := fset.File(node.Pos())
+	start := file.LineStart(file.Line(node.Pos()))
+
+	endLine := file.Line(node.End())
+	if endLine < file.LineCount() {
+		return start, file.LineStart(endLine + 1)
+	}
+	return start, token.Pos(file.Base() + file.Size())
+}
+
+// LineEnd returns the position of the newline ending the line that holds
+// pos, or the end of the file for the last line. Text inserted there follows
+// any trailing comment on that line instead of displacing it.
+func LineEnd(fset *token.FileSet, pos token.Pos) token.Pos {
+	file := fset.File(pos)
+	line := file.Line(pos)
+	if line < file.LineCount() {
+		return file.LineStart(line+1) - 1
+	}
+	return token.Pos(file.Base() + file.Size())
+}
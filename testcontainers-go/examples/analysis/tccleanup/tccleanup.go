@@ -0,0 +1,283 @@
+// Package tccleanup defines an Analyzer that checks that containers started
+// with testcontainers.Run, or the Run function of a module, are registered
+// for cleanup before their error is checked.
+//
+// When Run fails it may still return a container, for example when the
+// wait strategy times out. Checking the error first, as in
+//
+//	ctr, err := postgres.Run(ctx, "postgres:16-alpine")
+//	require.NoError(t, err)
+//	testcontainers.CleanupContainer(t, ctr)
+//
+// stops the test before the cleanup is registered and leaks that container.
+// The cleanup must come first:
+//
+//	ctr, err := postgres.Run(ctx, "postgres:16-alpine")
+//	testcontainers.CleanupContainer(t, ctr)
+//	require.NoError(t, err)
+package tccleanup
+
+import (
+	"fmt"
+	"go/ast"
+	"go/types"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/ast/inspector"
+
+	"github.com/testcontainers/testcontainers-go/examples/analysis/internal/tcast"
+)
+
+const doc = `check that containers are registered for cleanup before their error is checked
+
+Calls to testcontainers.Run, or to the Run function of a module such as
+postgres.Run, must be followed by testcontainers.CleanupContainer (or an
+equivalent t.Cleanup or defer) before the returned error is inspected.
+Otherwise a container returned together with an error is never terminated.`
+
+// Analyzer reports containers whose cleanup is missing or registered after
+// the error check.
+var Analyzer = &analysis.Analyzer{
+	Name:     "tccleanup",
+	Doc:      doc,
+	Requires: []*analysis.Analyzer{inspect.Analyzer},
+	Run:      run,
+}
+
+func run(pass *analysis.Pass) (any, error) {
+	insp := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
+
+	insp.WithStack([]ast.Node{(*ast.BlockStmt)(nil)}, func(n ast.Node, push bool, stack []ast.Node) bool {
+		if !push {
+			return true
+		}
+
+		block := n.(*ast.BlockStmt)
+		for i, stmt := range block.List {
+			checkRun(pass, stack, stmt, block.List[i+1:])
+		}
+		return true
+	})
+
+	return nil, nil
+}
+
+// checkRun inspects stmt, and when it assigns the result of a Run call, the
+// statements following it in the same block.
+func checkRun(pass *analysis.Pass, stack []ast.Node, stmt ast.Stmt, rest []ast.Stmt) {
+	assign, ok := stmt.(*ast.AssignStmt)
+	if !ok || len(assign.Lhs) != 2 || len(assign.Rhs) != 1 {
+		return
+	}
+	call, ok := ast.Unparen(assign.Rhs[0]).(*ast.CallExpr)
+	if !ok {
+		return
+	}
+	fn := tcast.RunFunc(pass.TypesInfo, call)
+	if fn == nil {
+		return
+	}
+
+	ctrID, ok := assign.Lhs[0].(*ast.Ident)
+	if !ok || ctrID.Name == "_" {
+		// Stored in a field or discarded: the lifetime is managed elsewhere.
+		return
+	}
+	ctr := pass.TypesInfo.ObjectOf(ctrID)
+
+	var errObj types.Object
+	if id, ok := assign.Lhs[1].(*ast.Ident); ok && id.Name != "_" {
+		errObj = pass.TypesInfo.ObjectOf(id)
+	}
+
+	for _, s := range rest {
+		if escapes(pass.TypesInfo, s, ctr) {
+			// Returned or stored elsewhere: its owner is responsible for it.
+			return
+		}
+	}
+
+	for i, s := range rest {
+		cleanup := cleanupCall(pass.TypesInfo, s, ctr) != nil
+		checked := errObj != nil && tcast.Uses(pass.TypesInfo, s, errObj)
+
+		switch {
+		case cleanup && !checked:
+			return
+		case checked:
+			reportLateCleanup(pass, stack, assign, fn, ctrID, rest[i:])
+			return
+		}
+	}
+
+	reportMissingCleanup(pass, stack, assign, fn, ctrID)
+}
+
+// reportLateCleanup reports a container whose error is checked by rest[0]
+// before any cleanup is registered, moving a later CleanupContainer call up
+// when there is one.
+func reportLateCleanup(pass *analysis.Pass, stack []ast.Node, assign *ast.AssignStmt, fn *types.Func, ctrID *ast.Ident, rest []ast.Stmt) {
+	ctr := pass.TypesInfo.ObjectOf(ctrID)
+
+	var (
+		late ast.Stmt
+		call *ast.CallExpr
+	)
+	for _, s := range rest {
+		if call = cleanupCall(pass.TypesInfo, s, ctr); call != nil {
+			late = s
+			break
+		}
+	}
+	if late == nil {
+		reportMissingCleanup(pass, stack, assign, fn, ctrID)
+		return
+	}
+
+	diag := analysis.Diagnostic{
+		Pos:     call.Pos(),
+		End:     call.End(),
+		Message: fmt.Sprintf("cleanup of %s is registered after the error of %s.%s is checked, leaking the container when it fails to start", ctrID.Name, fn.Pkg().Name(), fn.Name()),
+	}
+
+	if src, ok := movableText(pass, late); ok && late != rest[0] {
+		start, end := tcast.LineRange(pass.Fset, late)
+		insert := tcast.LineEnd(pass.Fset, assign.End())
+		text := fmt.Sprintf("\n%s%s", tcast.Indent(pass.Fset, assign.Pos()), src)
+
+		diag.SuggestedFixes = []analysis.SuggestedFix{{
+			Message: "Register the cleanup before checking the error",
+			TextEdits: []analysis.TextEdit{
+				{Pos: insert, End: insert, NewText: []byte(text)},
+				{Pos: start, End: end},
+			},
+		}}
+	}
+
+	pass.Report(diag)
+}
+
+// reportMissingCleanup reports a container that is never registered for
+// cleanup, suggesting a CleanupContainer call when a *testing.T is in scope.
+func reportMissingCleanup(pass *analysis.Pass, stack []ast.Node, assign *ast.AssignStmt, fn *types.Func, ctrID *ast.Ident) {
+	diag := analysis.Diagnostic{
+		Pos:     assign.Pos(),
+		End:     assign.End(),
+		Message: fmt.Sprintf("container %s started by %s.%s is never registered for cleanup", ctrID.Name, fn.Pkg().Name(), fn.Name()),
+	}
+
+	file, _ := stack[0].(*ast.File)
+	tcName := ""
+	if file != nil {
+		tcName = tcast.ImportName(file, tcast.CorePath)
+	}
+	tName := tcast.TestingParam(pass.TypesInfo, stack)
+
+	if tcName != "" && tName != "" {
+		end := tcast.LineEnd(pass.Fset, assign.End())
+		text := fmt.Sprintf("\n%s%s.CleanupContainer(%s, %s)", tcast.Indent(pass.Fset, assign.Pos()), tcName, tName, ctrID.Name)
+
+		diag.SuggestedFixes = []analysis.SuggestedFix{{
+			Message: "Register the container with testcontainers.CleanupContainer",
+			TextEdits: []analysis.TextEdit{
+				{Pos: end, End: end, NewText: []byte(text)},
+			},
+		}}
+	}
+
+	pass.Report(diag)
+}
+
+// cleanupCall returns the call in stmt that terminates ctr or registers it
+// for termination: testcontainers.CleanupContainer(t, ctr),
+// testcontainers.TerminateContainer(ctr) or ctr.Terminate(ctx), including
+// when wrapped in a defer or t.Cleanup. It returns nil if there is none.
+func cleanupCall(info *types.Info, stmt ast.Stmt, ctr types.Object) *ast.CallExpr {
+	var found *ast.CallExpr
+	ast.Inspect(stmt, func(n ast.Node) bool {
+		call, ok := n.(*ast.CallExpr)
+		if !ok || found != nil {
+			return found == nil
+		}
+
+		var arg ast.Expr
+		switch {
+		case tcast.IsCoreFunc(info, call, "CleanupContainer") && len(call.Args) >= 2:
+			arg = call.Args[1]
+		case tcast.IsCoreFunc(info, call, "TerminateContainer") && len(call.Args) >= 1:
+			arg = call.Args[0]
+		default:
+			if sel, ok := call.Fun.(*ast.SelectorExpr); ok && sel.Sel.Name == "Terminate" {
+				arg = sel.X
+			}
+		}
+		if arg != nil && refersTo(info, arg, ctr) {
+			found = call
+		}
+		return found == nil
+	})
+	return found
+}
+
+// escapes reports whether stmt hands ctr over to someone else, by returning
+// it, storing it in another variable, field or composite literal, or
+// reassigning the variable.
+func escapes(info *types.Info, stmt ast.Stmt, ctr types.Object) bool {
+	found := false
+	ast.Inspect(stmt, func(n ast.Node) bool {
+		switch s := n.(type) {
+		case *ast.ReturnStmt:
+			for _, r := range s.Results {
+				found = found || refersTo(info, r, ctr)
+			}
+		case *ast.AssignStmt:
+			for _, lhs := range s.Lhs {
+				found = found || refersTo(info, lhs, ctr)
+			}
+			for i, rhs := range s.Rhs {
+				stored := len(s.Lhs) != len(s.Rhs) || !isBlank(s.Lhs[i])
+				found = found || (stored && refersTo(info, rhs, ctr))
+			}
+		case *ast.CompositeLit:
+			for _, elt := range s.Elts {
+				if kv, ok := elt.(*ast.KeyValueExpr); ok {
+					elt = kv.Value
+				}
+				found = found || refersTo(info, elt, ctr)
+			}
+		case *ast.FuncLit:
+			// Returning from a closure does not return the container.
+			return false
+		}
+		return !found
+	})
+	return found
+}
+
+// isBlank reports whether expr is the blank identifier.
+func isBlank(expr ast.Expr) bool {
+	id, ok := expr.(*ast.Ident)
+	return ok && id.Name == "_"
+}
+
+// refersTo reports whether expr is an identifier for obj.
+func refersTo(info *types.Info, expr ast.Expr, obj types.Object) bool {
+	id, ok := ast.Unparen(expr).(*ast.Ident)
+	return ok && info.ObjectOf(id) == obj
+}
+
+// movableText returns the source text of stmt if it is a plain call
+// statement, which can be moved without changing its meaning.
+func movableText(pass *analysis.Pass, stmt ast.Stmt) (string, bool) {
+	if _, ok := stmt.(*ast.ExprStmt); !ok {
+		return "", false
+	}
+
+	file := pass.Fset.File(stmt.Pos())
+	content, err := pass.ReadFile(file.Name())
+	if err != nil {
+		return "", false
+	}
+	return string(content[file.Offset(stmt.Pos()):file.Offset(stmt.End())]), true
+}
@@ -0,0 +1,183 @@
+// Package tcsleep defines an Analyzer that reports time.Sleep calls in
+// functions that hold a testcontainers container.
+//
+// Sleeping for a fixed amount of time is the classic source of flaky
+// container tests: too short on a slow CI runner, wasted time everywhere
+// else. The analyzer suggests the replacement that fits what the sleep is
+// waiting for:
+//
+//   - wait.ForLog, when the next statement reads the container logs or runs
+//     a command that depends on the container's own startup work;
+//   - wait.ForListeningPort, when the next statement connects to a port;
+//   - require.Eventually, for anything else, such as waiting for a key to
+//     expire.
+package tcsleep
+
+import (
+	"fmt"
+	"go/ast"
+	"go/types"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/ast/inspector"
+	"golang.org/x/tools/go/types/typeutil"
+
+	"github.com/testcontainers/testcontainers-go/examples/analysis/internal/tcast"
+)
+
+const doc = `report time.Sleep in functions that hold a testcontainers container
+
+A test must wait for a container with a wait strategy (wait.ForLog,
+wait.ForListeningPort, ...) or poll for the condition it expects with
+require.Eventually, never with a fixed time.Sleep.`
+
+// Analyzer reports time.Sleep calls next to testcontainers containers.
+var Analyzer = &analysis.Analyzer{
+	Name:     "tcsleep",
+	Doc:      doc,
+	Requires: []*analysis.Analyzer{inspect.Analyzer},
+	Run:      run,
+}
+
+// portMethods are the Container methods used to reach a mapped port.
+var portMethods = map[string]bool{
+	"ConnectionString": true,
+	"Endpoint":         true,
+	"Host":             true,
+	"MappedPort":       true,
+	"PortEndpoint":     true,
+}
+
+// startupMethods are the Container methods that observe work the container
+// does on its own once started.
+var startupMethods = map[string]bool{
+	"CopyFileFromContainer": true,
+	"Exec":                  true,
+	"Logs":                  true,
+}
+
+func run(pass *analysis.Pass) (any, error) {
+	container := tcast.ContainerInterface(pass.Pkg)
+	if container == nil {
+		// The package cannot hold a container without importing the core.
+		return nil, nil
+	}
+
+	insp := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
+
+	insp.Preorder([]ast.Node{(*ast.FuncDecl)(nil)}, func(n ast.Node) {
+		fn := n.(*ast.FuncDecl)
+		if fn.Body == nil {
+			return
+		}
+
+		holder := containerVar(pass.TypesInfo, fn, container)
+		if holder == nil {
+			return
+		}
+
+		ast.Inspect(fn.Body, func(n ast.Node) bool {
+			block, ok := n.(*ast.BlockStmt)
+			if !ok {
+				return true
+			}
+			for i, stmt := range block.List {
+				call := sleepCall(pass.TypesInfo, stmt)
+				if call == nil {
+					continue
+				}
+
+				var next ast.Stmt
+				if i+1 < len(block.List) {
+					next = block.List[i+1]
+				}
+
+				pass.Report(analysis.Diagnostic{
+					Pos: call.Pos(),
+					End: call.End(),
+					Message: fmt.Sprintf("time.Sleep in a function holding container %s: %s",
+						holder.Name(), replacement(pass.TypesInfo, next, container)),
+				})
+			}
+			return true
+		})
+	})
+
+	return nil, nil
+}
+
+// containerVar returns the first parameter or variable declared by fn whose
+// type implements testcontainers.Container, or nil if there is none.
+func containerVar(info *types.Info, fn *ast.FuncDecl, container *types.Interface) types.Object {
+	var found types.Object
+	ast.Inspect(fn, func(n ast.Node) bool {
+		if found != nil {
+			return false
+		}
+		id, ok := n.(*ast.Ident)
+		if !ok {
+			return true
+		}
+		if obj, ok := info.Defs[id].(*types.Var); ok && types.Implements(obj.Type(), container) {
+			found = obj
+		}
+		return true
+	})
+	return found
+}
+
+// sleepCall returns the time.Sleep call made by stmt, if any.
+func sleepCall(info *types.Info, stmt ast.Stmt) *ast.CallExpr {
+	expr, ok := stmt.(*ast.ExprStmt)
+	if !ok {
+		return nil
+	}
+	call, ok := ast.Unparen(expr.X).(*ast.CallExpr)
+	if !ok || !tcast.IsFunc(info, call, "time", "Sleep") {
+		return nil
+	}
+	return call
+}
+
+// replacement describes what should replace a sleep followed by next.
+func replacement(info *types.Info, next ast.Stmt, container *types.Interface) string {
+	method := containerMethod(info, next, container)
+	switch {
+	case portMethods[method]:
+		return "wait for the port with testcontainers.WithWaitStrategy(wait.ForListeningPort(...)) instead"
+	case startupMethods[method]:
+		return "wait for a readiness log line with testcontainers.WithWaitStrategy(wait.ForLog(...)) instead"
+	default:
+		return "poll for the expected condition with require.Eventually instead"
+	}
+}
+
+// containerMethod returns the name of the first container method called by
+// stmt, or "" if there is none.
+func containerMethod(info *types.Info, stmt ast.Stmt, container *types.Interface) string {
+	if stmt == nil {
+		return ""
+	}
+
+	name := ""
+	ast.Inspect(stmt, func(n ast.Node) bool {
+		if name != "" {
+			return false
+		}
+		call, ok := n.(*ast.CallExpr)
+		if !ok {
+			return true
+		}
+		fn, ok := typeutil.Callee(info, call).(*types.Func)
+		if !ok {
+			return true
+		}
+		recv := fn.Type().(*types.Signature).Recv()
+		if recv != nil && types.Implements(recv.Type(), container) {
+			name = fn.Name()
+		}
+		return name == ""
+	})
+	return name
+}
@@ -0,0 +1,19 @@
+package tcsleep_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"golang.org/x/tools/go/analysis/analysistest"
+
+	"github.com/testcontainers/testcontainers-go/examples/analysis/tcsleep"
+)
+
+func TestAnalyzer(t *testing.T) {
+	testdata, err := filepath.Abs(filepath.Join("..", "testdata"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	analysistest.Run(t, testdata, tcsleep.Analyzer, "tcsleep")
+}
@@ -0,0 +1,352 @@
+// Package tcwait defines an Analyzer that reports ports exposed with
+// testcontainers.WithExposedPorts that no wait strategy checks.
+//
+// Without a wait strategy, Run returns as soon as the container is started,
+// before the service inside it listens on its port:
+//
+//	ctr, err := testcontainers.Run(ctx, "nginx:alpine",
+//		testcontainers.WithExposedPorts("80/tcp"),
+//	)
+//
+// Every exposed port must be covered by a wait strategy, either one that
+// targets the port, such as wait.ForListeningPort("80/tcp"), or one that
+// checks the whole container, such as wait.ForLog.
+//
+// Options that bundle a wait strategy, such as postgres.BasicWaitStrategies,
+// and module Run functions that set their own, such as redis.Run, are
+// recognized through facts exported for every function that calls
+// testcontainers.WithWaitStrategy or one of its variants.
+package tcwait
+
+import (
+	"fmt"
+	"go/ast"
+	"go/constant"
+	"go/types"
+	"sort"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/ast/inspector"
+	"golang.org/x/tools/go/types/typeutil"
+
+	"github.com/testcontainers/testcontainers-go/examples/analysis/internal/tcast"
+)
+
+const doc = `report exposed ports that no wait strategy checks
+
+Every port passed to testcontainers.WithExposedPorts must be covered by a
+wait strategy, given with testcontainers.WithWaitStrategy or bundled by the
+module, so that the test does not race the service starting up.`
+
+// Analyzer reports exposed ports without a wait strategy.
+var Analyzer = &analysis.Analyzer{
+	Name:      "tcwait",
+	Doc:       doc,
+	Requires:  []*analysis.Analyzer{inspect.Analyzer},
+	Run:       run,
+	FactTypes: []analysis.Fact{new(waitFact)},
+}
+
+const waitPath = tcast.CorePath + "/wait"
+
+// waitFact is exported for functions that set a wait strategy when called,
+// such as postgres.BasicWaitStrategies or redis.Run.
+type waitFact struct {
+	// All is set when the strategy checks the whole container.
+	All bool
+	// Ports lists the ports checked by the strategy otherwise.
+	Ports []string
+}
+
+func (*waitFact) AFact() {}
+
+func (f *waitFact) String() string {
+	if f.All {
+		return "waits(all)"
+	}
+	return fmt.Sprintf("waits(%s)", strings.Join(f.Ports, ", "))
+}
+
+// coverage is the set of ports checked by wait strategies.
+type coverage struct {
+	all   bool
+	ports map[string]bool
+}
+
+func (c *coverage) add(o coverage) {
+	c.all = c.all || o.all
+	for p := range o.ports {
+		c.addPort(p)
+	}
+}
+
+func (c *coverage) addPort(port string) {
+	if c.ports == nil {
+		c.ports = make(map[string]bool)
+	}
+	c.ports[port] = true
+}
+
+func (c coverage) empty() bool {
+	return !c.all && len(c.ports) == 0
+}
+
+func (c coverage) covers(port string) bool {
+	return c.all || c.ports[port]
+}
+
+func (c coverage) equal(o coverage) bool {
+	if c.all != o.all || len(c.ports) != len(o.ports) {
+		return false
+	}
+	for p := range c.ports {
+		if !o.ports[p] {
+			return false
+		}
+	}
+	return true
+}
+
+func (c coverage) fact() *waitFact {
+	f := &waitFact{All: c.all}
+	for p := range c.ports {
+		f.Ports = append(f.Ports, p)
+	}
+	sort.Strings(f.Ports)
+	return f
+}
+
+var everything = coverage{all: true}
+
+func run(pass *analysis.Pass) (any, error) {
+	exportFacts(pass)
+
+	insp := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
+
+	insp.Preorder([]ast.Node{(*ast.CallExpr)(nil)}, func(n ast.Node) {
+		call := n.(*ast.CallExpr)
+		fn := tcast.RunFunc(pass.TypesInfo, call)
+		if fn == nil || call.Ellipsis.IsValid() || len(call.Args) < 2 {
+			// Options passed as a slice cannot be inspected.
+			return
+		}
+		checkRun(pass, fn, call.Args[2:])
+	})
+
+	return nil, nil
+}
+
+// exposedPort is a constant port passed to testcontainers.WithExposedPorts.
+type exposedPort struct {
+	expr ast.Expr
+	port string
+}
+
+// checkRun reports the ports exposed by opts that neither opts nor the Run
+// function fn itself cover with a wait strategy.
+func checkRun(pass *analysis.Pass, fn *types.Func, opts []ast.Expr) {
+	var (
+		waits   coverage
+		exposed []exposedPort
+	)
+
+	var fact waitFact
+	if pass.ImportObjectFact(fn, &fact) {
+		waits.add(factCoverage(&fact))
+	}
+
+	for _, opt := range opts {
+		call, ok := ast.Unparen(opt).(*ast.CallExpr)
+		if !ok {
+			// An option held in a variable may well be a wait strategy.
+			return
+		}
+
+		if tcast.IsCoreFunc(pass.TypesInfo, call, "WithExposedPorts") {
+			for _, arg := range call.Args {
+				if port, ok := portValue(pass.TypesInfo, arg); ok {
+					exposed = append(exposed, exposedPort{expr: arg, port: port})
+				}
+			}
+			continue
+		}
+
+		waits.add(callCoverage(pass, call))
+	}
+
+	for _, e := range exposed {
+		if waits.covers(e.port) {
+			continue
+		}
+
+		msg := "port %s is exposed without a wait strategy: add testcontainers.WithWaitStrategy(wait.ForListeningPort(%q))"
+		if !waits.empty() {
+			msg = "port %s is exposed but no wait strategy checks it: add wait.ForListeningPort(%q)"
+		}
+		pass.Reportf(e.expr.Pos(), msg, e.port, e.port)
+	}
+}
+
+// exportFacts exports a waitFact for every function of the package that sets
+// a wait strategy, directly or by calling another such function. Functions
+// without results cannot be passed as an option and are skipped.
+func exportFacts(pass *analysis.Pass) {
+	var decls []*ast.FuncDecl
+	for _, f := range pass.Files {
+		for _, decl := range f.Decls {
+			if fd, ok := decl.(*ast.FuncDecl); ok && fd.Body != nil && fd.Type.Results != nil {
+				decls = append(decls, fd)
+			}
+		}
+	}
+
+	// Functions may call each other in any order: iterate until no new
+	// function is found to set a wait strategy.
+	for changed := true; changed; {
+		changed = false
+		for _, fd := range decls {
+			obj := pass.TypesInfo.Defs[fd.Name]
+			if obj == nil {
+				continue
+			}
+
+			var c coverage
+			ast.Inspect(fd.Body, func(n ast.Node) bool {
+				if call, ok := n.(*ast.CallExpr); ok {
+					c.add(callCoverage(pass, call))
+				}
+				return true
+			})
+			if c.empty() {
+				continue
+			}
+
+			var old waitFact
+			if pass.ImportObjectFact(obj, &old) && factCoverage(&old).equal(c) {
+				continue
+			}
+			pass.ExportObjectFact(obj, c.fact())
+			changed = true
+		}
+	}
+}
+
+func factCoverage(f *waitFact) coverage {
+	c := coverage{all: f.All}
+	for _, p := range f.Ports {
+		c.addPort(p)
+	}
+	return c
+}
+
+// callCoverage returns the ports covered by the wait strategies that call
+// sets, when it is testcontainers.WithWaitStrategy or one of its variants,
+// or a function known to call one.
+func callCoverage(pass *analysis.Pass, call *ast.CallExpr) coverage {
+	fn, ok := typeutil.Callee(pass.TypesInfo, call).(*types.Func)
+	if !ok {
+		return coverage{}
+	}
+
+	if fn.Pkg() != nil && fn.Pkg().Path() == tcast.CorePath {
+		var strategies []ast.Expr
+		switch fn.Name() {
+		case "WithWaitStrategy", "WithAdditionalWaitStrategy":
+			strategies = call.Args
+		case "WithWaitStrategyAndDeadline", "WithAdditionalWaitStrategyAndDeadline":
+			if len(call.Args) > 0 {
+				strategies = call.Args[1:]
+			}
+		default:
+			return coverage{}
+		}
+		return strategiesCoverage(pass.TypesInfo, strategies, call.Ellipsis.IsValid())
+	}
+
+	var fact waitFact
+	if pass.ImportObjectFact(fn, &fact) {
+		return factCoverage(&fact)
+	}
+	return coverage{}
+}
+
+// strategiesCoverage returns the ports covered by a list of strategies,
+// which are unknown when passed as a slice.
+func strategiesCoverage(info *types.Info, strategies []ast.Expr, spread bool) coverage {
+	if spread {
+		return everything
+	}
+
+	var c coverage
+	for _, s := range strategies {
+		c.add(strategyCoverage(info, s))
+	}
+	return c
+}
+
+// strategyCoverage returns the ports covered by the strategy expr. Strategies
+// that target a port cover only that port, the others cover the whole
+// container.
+func strategyCoverage(info *types.Info, expr ast.Expr) coverage {
+	call, ok := ast.Unparen(expr).(*ast.CallExpr)
+	if !ok {
+		return everything
+	}
+	fn, ok := typeutil.Callee(info, call).(*types.Func)
+	if !ok || fn.Pkg() == nil || fn.Pkg().Path() != waitPath {
+		return everything
+	}
+
+	if fn.Type().(*types.Signature).Recv() != nil {
+		// Builder methods such as WithStartupTimeout keep the port of the
+		// strategy they are called on, except WithPort which sets it.
+		sel, ok := call.Fun.(*ast.SelectorExpr)
+		switch {
+		case fn.Name() == "WithPort" && len(call.Args) == 1:
+			return portCoverage(info, call.Args[0])
+		case ok:
+			return strategyCoverage(info, sel.X)
+		default:
+			return everything
+		}
+	}
+
+	switch fn.Name() {
+	case "ForListeningPort", "ForMappedPort", "ForSQL":
+		if len(call.Args) > 0 {
+			return portCoverage(info, call.Args[0])
+		}
+	case "ForAll":
+		return strategiesCoverage(info, call.Args, call.Ellipsis.IsValid())
+	}
+	return everything
+}
+
+// portCoverage returns the coverage of a strategy checking the port expr,
+// which is the whole container when the port is not a constant.
+func portCoverage(info *types.Info, expr ast.Expr) coverage {
+	port, ok := portValue(info, expr)
+	if !ok {
+		return everything
+	}
+	var c coverage
+	c.addPort(port)
+	return c
+}
+
+// portValue returns the constant port expr evaluates to, with the protocol
+// defaulting to tcp as it does in testcontainers.
+func portValue(info *types.Info, expr ast.Expr) (string, bool) {
+	tv, ok := info.Types[expr]
+	if !ok || tv.Value == nil || tv.Value.Kind() != constant.String {
+		return "", false
+	}
+
+	port := constant.StringVal(tv.Value)
+	if !strings.Contains(port, "/") {
+		port += "/tcp"
+	}
+	return port, true
+}
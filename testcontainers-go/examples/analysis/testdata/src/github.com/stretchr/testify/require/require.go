@@ -0,0 +1,9 @@
+// Package require is a minimal stand-in for the real package.
+package require
+
+type TestingT interface {
+	Errorf(format string, args ...any)
+	FailNow()
+}
+
+func NoError(t TestingT, err error, msgAndArgs ...any) {}
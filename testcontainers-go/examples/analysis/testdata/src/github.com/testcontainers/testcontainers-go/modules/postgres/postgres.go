@@ -0,0 +1,24 @@
+// Package postgres is a minimal stand-in for the real module.
+package postgres
+
+import (
+	"context"
+
+	"github.com/testcontainers/testcontainers-go"
+	"github.com/testcontainers/testcontainers-go/wait"
+)
+
+type PostgresContainer struct{ testcontainers.Container }
+
+func Run(ctx context.Context, img string, opts ...testcontainers.ContainerCustomizer) (*PostgresContainer, error) {
+	return nil, nil
+}
+
+func WithDatabase(dbName string) testcontainers.CustomizeRequestOption { return nil }
+
+func BasicWaitStrategies() testcontainers.CustomizeRequestOption {
+	return testcontainers.WithAdditionalWaitStrategy(
+		wait.ForLog("database system is ready to accept connections"),
+		wait.ForListeningPort("5432/tcp"),
+	)
+}
@@ -0,0 +1,17 @@
+// Package redis is a minimal stand-in for the real module.
+package redis
+
+import (
+	"context"
+
+	"github.com/testcontainers/testcontainers-go"
+	"github.com/testcontainers/testcontainers-go/wait"
+)
+
+type RedisContainer struct{ testcontainers.Container }
+
+func Run(ctx context.Context, img string, opts ...testcontainers.ContainerCustomizer) (*RedisContainer, error) {
+	waitStrategies := []wait.Strategy{wait.ForLog("* Ready to accept connections")}
+	_ = testcontainers.WithWaitStrategy(waitStrategies...)
+	return nil, nil
+}
@@ -0,0 +1,51 @@
+// Package testcontainers is a minimal stand-in for the real package, with
+// just enough API for the analyzer tests.
+package testcontainers
+
+import (
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/testcontainers/testcontainers-go/wait"
+)
+
+type Container interface {
+	Endpoint(ctx context.Context, proto string) (string, error)
+	Exec(ctx context.Context, cmd []string) (int, io.Reader, error)
+	Host(ctx context.Context) (string, error)
+	Logs(ctx context.Context) (io.ReadCloser, error)
+	MappedPort(ctx context.Context, port string) (string, error)
+	Terminate(ctx context.Context, opts ...TerminateOption) error
+}
+
+type DockerContainer struct{ Container }
+
+type ContainerCustomizer interface{ Customize() error }
+
+type CustomizeRequestOption func() error
+
+func (o CustomizeRequestOption) Customize() error { return o() }
+
+type TerminateOption func()
+
+func Run(ctx context.Context, img string, opts ...ContainerCustomizer) (*DockerContainer, error) {
+	return nil, nil
+}
+
+func CleanupContainer(tb testing.TB, ctr Container, options ...TerminateOption) {}
+
+func TerminateContainer(container Container, options ...TerminateOption) error { return nil }
+
+func WithCmd(cmd ...string) CustomizeRequestOption { return nil }
+
+func WithExposedPorts(ports ...string) CustomizeRequestOption { return nil }
+
+func WithWaitStrategy(strategies ...wait.Strategy) CustomizeRequestOption { return nil }
+
+func WithAdditionalWaitStrategy(strategies ...wait.Strategy) CustomizeRequestOption { return nil }
+
+func WithWaitStrategyAndDeadline(deadline time.Duration, strategies ...wait.Strategy) CustomizeRequestOption {
+	return nil
+}
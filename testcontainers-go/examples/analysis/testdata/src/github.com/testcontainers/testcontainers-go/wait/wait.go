@@ -0,0 +1,30 @@
+// Package wait is a minimal stand-in for the real package.
+package wait
+
+import "time"
+
+type Strategy interface{ WaitUntilReady() error }
+
+type HostPortStrategy struct{ Strategy }
+
+func ForListeningPort(port string) *HostPortStrategy { return nil }
+
+type LogStrategy struct{ Strategy }
+
+func ForLog(log string) *LogStrategy { return nil }
+
+func (s *HostPortStrategy) WithStartupTimeout(timeout time.Duration) *HostPortStrategy { return s }
+
+func ForMappedPort(port string) *HostPortStrategy { return nil }
+
+type HTTPStrategy struct{ Strategy }
+
+func ForHTTP(path string) *HTTPStrategy { return nil }
+
+func (s *HTTPStrategy) WithPort(port string) *HTTPStrategy { return s }
+
+func (s *HTTPStrategy) WithStartupTimeout(timeout time.Duration) *HTTPStrategy { return s }
+
+type MultiStrategy struct{ Strategy }
+
+func ForAll(strategies ...Strategy) *MultiStrategy { return nil }
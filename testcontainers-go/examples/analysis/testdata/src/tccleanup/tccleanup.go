@@ -0,0 +1,98 @@
+package tccleanup
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/testcontainers/testcontainers-go"
+	"github.com/testcontainers/testcontainers-go/modules/postgres"
+	tcredis "github.com/testcontainers/testcontainers-go/modules/redis"
+)
+
+func TestCleanupFirst(t *testing.T) {
+	ctx := context.Background()
+
+	ctr, err := postgres.Run(ctx, "postgres:16-alpine")
+	testcontainers.CleanupContainer(t, ctr)
+	require.NoError(t, err)
+}
+
+func TestCleanupAfterCheck(t *testing.T) {
+	ctx := context.Background()
+
+	ctr, err := testcontainers.Run(ctx, "nginx:alpine")
+	require.NoError(t, err)
+	testcontainers.CleanupContainer(t, ctr) // want `cleanup of ctr is registered after the error of testcontainers.Run is checked`
+}
+
+func TestMissingCleanup(t *testing.T) {
+	ctx := context.Background()
+
+	redisContainer, err := tcredis.Run(ctx, "redis:7-alpine") // want `container redisContainer started by redis.Run is never registered for cleanup`
+	require.NoError(t, err)
+
+	_, _ = redisContainer.Endpoint(ctx, "redis")
+}
+
+func TestCleanupOnlyOnSuccess(t *testing.T) {
+	ctx := context.Background()
+
+	go func() {
+		ctr, err := postgres.Run(ctx, "postgres:16-alpine")
+		if err == nil {
+			testcontainers.CleanupContainer(t, ctr) // want `cleanup of ctr is registered after the error`
+		}
+	}()
+}
+
+func TestManualCleanupAfterCheck(t *testing.T) {
+	ctx := context.Background()
+
+	ctr, err := testcontainers.Run(ctx, "nginx:alpine")
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		require.NoError(t, testcontainers.TerminateContainer(ctr)) // want `cleanup of ctr is registered after the error`
+	})
+}
+
+func TestDeferredTerminate(t *testing.T) {
+	ctx := context.Background()
+
+	ctr, err := testcontainers.Run(ctx, "nginx:alpine")
+	defer func() {
+		_ = ctr.Terminate(ctx)
+	}()
+	require.NoError(t, err)
+}
+
+func startPostgres(ctx context.Context) (*postgres.PostgresContainer, error) {
+	ctr, err := postgres.Run(ctx, "postgres:16-alpine")
+	if err != nil {
+		return ctr, err
+	}
+	return ctr, nil
+}
+
+type fixture struct {
+	ctr *postgres.PostgresContainer
+}
+
+func startFixture(ctx context.Context) (*fixture, error) {
+	ctr, err := postgres.Run(ctx, "postgres:16-alpine")
+	if err != nil {
+		return nil, err
+	}
+	return &fixture{ctr: ctr}, nil
+}
+
+func TestMainStyle(m *testing.M) {
+	ctx := context.Background()
+
+	ctr, err := postgres.Run(ctx, "postgres:16-alpine") // want `container ctr started by postgres.Run is never registered for cleanup`
+	if err != nil {
+		panic(err)
+	}
+	_ = ctr
+	_ = m
+}
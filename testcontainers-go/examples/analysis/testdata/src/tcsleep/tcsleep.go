@@ -0,0 +1,63 @@
+package tcsleep
+
+import (
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/testcontainers/testcontainers-go"
+	tcredis "github.com/testcontainers/testcontainers-go/modules/redis"
+)
+
+func TestSleepBeforeLogs(t *testing.T) {
+	ctx := context.Background()
+
+	ctr, err := testcontainers.Run(ctx, "alpine:latest")
+	testcontainers.CleanupContainer(t, ctr)
+	require.NoError(t, err)
+
+	time.Sleep(2 * time.Second) // want `time.Sleep in a function holding container ctr: wait for a readiness log line with testcontainers.WithWaitStrategy\(wait.ForLog\(...\)\) instead`
+
+	logs, err := ctr.Logs(ctx)
+	require.NoError(t, err)
+	_, _ = io.ReadAll(logs)
+}
+
+func TestSleepBeforeEndpoint(t *testing.T) {
+	ctx := context.Background()
+
+	ctr, err := testcontainers.Run(ctx, "nginx:alpine", testcontainers.WithExposedPorts("80/tcp"))
+	testcontainers.CleanupContainer(t, ctr)
+	require.NoError(t, err)
+
+	time.Sleep(time.Second) // want `wait for the port with testcontainers.WithWaitStrategy\(wait.ForListeningPort\(...\)\) instead`
+
+	endpoint, err := ctr.Endpoint(ctx, "http")
+	require.NoError(t, err)
+	_ = endpoint
+}
+
+func TestSleepForExpiration(t *testing.T) {
+	ctx := context.Background()
+
+	redisContainer, err := tcredis.Run(ctx, "redis:7-alpine")
+	testcontainers.CleanupContainer(t, redisContainer)
+	require.NoError(t, err)
+
+	t.Run("Expiration", func(t *testing.T) {
+		time.Sleep(3 * time.Second) // want `time.Sleep in a function holding container redisContainer: poll for the expected condition with require.Eventually instead`
+		require.NoError(t, nil)
+	})
+}
+
+func TestSleepWithoutContainer(t *testing.T) {
+	time.Sleep(time.Millisecond)
+}
+
+func waitForLogs(ctx context.Context, ctr testcontainers.Container) error {
+	time.Sleep(time.Second) // want `time.Sleep in a function holding container ctr`
+	_, err := ctr.Logs(ctx)
+	return err
+}
@@ -0,0 +1,142 @@
+package tcwait
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/testcontainers/testcontainers-go"
+	"github.com/testcontainers/testcontainers-go/modules/postgres"
+	tcredis "github.com/testcontainers/testcontainers-go/modules/redis"
+	"github.com/testcontainers/testcontainers-go/wait"
+)
+
+func TestNoWaitStrategy(t *testing.T) {
+	ctx := context.Background()
+
+	ctr, err := testcontainers.Run(ctx, "nginx:alpine",
+		testcontainers.WithExposedPorts("80/tcp"), // want `port 80/tcp is exposed without a wait strategy: add testcontainers.WithWaitStrategy\(wait.ForListeningPort\("80/tcp"\)\)`
+	)
+	testcontainers.CleanupContainer(t, ctr)
+	require.NoError(t, err)
+}
+
+func TestNoExposedPorts(t *testing.T) {
+	ctx := context.Background()
+
+	ctr, err := testcontainers.Run(ctx, "alpine:latest", testcontainers.WithCmd("sleep", "300"))
+	testcontainers.CleanupContainer(t, ctr)
+	require.NoError(t, err)
+}
+
+func TestListeningPort(t *testing.T) {
+	ctx := context.Background()
+
+	ctr, err := testcontainers.Run(ctx, "nginx:alpine",
+		testcontainers.WithExposedPorts("80"),
+		testcontainers.WithWaitStrategy(wait.ForListeningPort("80/tcp").WithStartupTimeout(time.Minute)),
+	)
+	testcontainers.CleanupContainer(t, ctr)
+	require.NoError(t, err)
+}
+
+func TestUncheckedPort(t *testing.T) {
+	ctx := context.Background()
+
+	ctr, err := testcontainers.Run(ctx, "nginx:alpine",
+		testcontainers.WithExposedPorts("80/tcp", "443/tcp"), // want `port 443/tcp is exposed but no wait strategy checks it: add wait.ForListeningPort\("443/tcp"\)`
+		testcontainers.WithWaitStrategy(wait.ForListeningPort("80/tcp")),
+	)
+	testcontainers.CleanupContainer(t, ctr)
+	require.NoError(t, err)
+}
+
+func TestHTTPWithPort(t *testing.T) {
+	ctx := context.Background()
+
+	ctr, err := testcontainers.Run(ctx, "nginx:alpine",
+		testcontainers.WithExposedPorts("80/tcp", "8080/tcp"), // want `port 8080/tcp is exposed but no wait strategy checks it`
+		testcontainers.WithWaitStrategyAndDeadline(time.Minute,
+			wait.ForAll(wait.ForHTTP("/").WithPort("80/tcp").WithStartupTimeout(time.Minute)),
+		),
+	)
+	testcontainers.CleanupContainer(t, ctr)
+	require.NoError(t, err)
+}
+
+func TestLogCoversAllPorts(t *testing.T) {
+	ctx := context.Background()
+
+	ctr, err := testcontainers.Run(ctx, "nginx:alpine",
+		testcontainers.WithExposedPorts("80/tcp", "443/tcp"),
+		testcontainers.WithWaitStrategy(wait.ForLog("start worker processes")),
+	)
+	testcontainers.CleanupContainer(t, ctr)
+	require.NoError(t, err)
+}
+
+func TestModuleDefaults(t *testing.T) {
+	ctx := context.Background()
+
+	pgContainer, err := postgres.Run(ctx, "postgres:16-alpine",
+		testcontainers.WithExposedPorts("5432/tcp"),
+		postgres.BasicWaitStrategies(),
+	)
+	testcontainers.CleanupContainer(t, pgContainer)
+	require.NoError(t, err)
+
+	redisContainer, err := tcredis.Run(ctx, "redis:7-alpine", testcontainers.WithExposedPorts("6379/tcp"))
+	testcontainers.CleanupContainer(t, redisContainer)
+	require.NoError(t, err)
+}
+
+func TestModuleWithoutDefaults(t *testing.T) {
+	ctx := context.Background()
+
+	pgContainer, err := postgres.Run(ctx, "postgres:16-alpine",
+		testcontainers.WithExposedPorts("5432/tcp"), // want `port 5432/tcp is exposed without a wait strategy`
+	)
+	testcontainers.CleanupContainer(t, pgContainer)
+	require.NoError(t, err)
+}
+
+// withNginxWait bundles a wait strategy like postgres.BasicWaitStrategies.
+func withNginxWait() testcontainers.CustomizeRequestOption { // want withNginxWait:`waits\(80/tcp\)`
+	return withListeningPort()
+}
+
+func withListeningPort() testcontainers.CustomizeRequestOption { // want withListeningPort:`waits\(80/tcp\)`
+	return testcontainers.WithAdditionalWaitStrategy(wait.ForListeningPort("80/tcp"))
+}
+
+func withPort(port string) testcontainers.CustomizeRequestOption { // want withPort:`waits\(all\)`
+	return testcontainers.WithAdditionalWaitStrategy(wait.ForListeningPort(port))
+}
+
+func TestLocalOption(t *testing.T) {
+	ctx := context.Background()
+
+	ctr, err := testcontainers.Run(ctx, "nginx:alpine",
+		testcontainers.WithExposedPorts("80/tcp"),
+		withNginxWait(),
+	)
+	testcontainers.CleanupContainer(t, ctr)
+	require.NoError(t, err)
+
+	ctr, err = testcontainers.Run(ctx, "nginx:alpine",
+		testcontainers.WithExposedPorts("8080/tcp"),
+		withPort("8080/tcp"),
+	)
+	testcontainers.CleanupContainer(t, ctr)
+	require.NoError(t, err)
+}
+
+func TestOptionVariable(t *testing.T) {
+	ctx := context.Background()
+
+	waitOpt := testcontainers.WithWaitStrategy(wait.ForListeningPort("80/tcp"))
+	ctr, err := testcontainers.Run(ctx, "nginx:alpine", testcontainers.WithExposedPorts("80/tcp"), waitOpt)
+	testcontainers.CleanupContainer(t, ctr)
+	require.NoError(t, err)
+}
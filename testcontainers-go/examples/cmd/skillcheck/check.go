@@ -0,0 +1,161 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/scanner"
+	"go/token"
+	"go/types"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// placeholderRe matches errors about unqualified identifiers the snippet
+// expects the reader to provide, such as NewUserRepository or User.
+var placeholderRe = regexp.MustCompile(`^undefined: ([A-Za-z_][A-Za-z0-9_]*)$`)
+
+// Problem is a compile error found in a snippet.
+type Problem struct {
+	Pos token.Position
+	Msg string
+}
+
+func (p Problem) String() string {
+	return fmt.Sprintf("%s: %s", p.Pos, p.Msg)
+}
+
+// checker type-checks wrapped snippets against the packages resolvable from
+// the Go module in dir.
+type checker struct {
+	dir  string
+	fset *token.FileSet
+	pkgs map[string]*types.Package
+	errs map[string]error
+}
+
+func newChecker(dir string) *checker {
+	return &checker{
+		dir:  dir,
+		fset: token.NewFileSet(),
+		pkgs: make(map[string]*types.Package),
+		errs: make(map[string]error),
+	}
+}
+
+// load resolves every import path used by files in a single go/packages call,
+// so that the module graph is only walked once.
+func (c *checker) load(files []*ast.File) error {
+	var paths []string
+	seen := make(map[string]bool)
+	for _, f := range files {
+		for _, spec := range f.Imports {
+			path, err := strconv.Unquote(spec.Path.Value)
+			if err != nil || seen[path] {
+				continue
+			}
+			seen[path] = true
+			paths = append(paths, path)
+		}
+	}
+	sort.Strings(paths)
+
+	if len(paths) == 0 {
+		return nil
+	}
+
+	cfg := &packages.Config{
+		Mode: packages.NeedName | packages.NeedTypes | packages.NeedImports | packages.NeedDeps,
+		Dir:  c.dir,
+		Fset: c.fset,
+	}
+	loaded, err := packages.Load(cfg, paths...)
+	if err != nil {
+		return fmt.Errorf("load packages: %w", err)
+	}
+
+	for _, pkg := range loaded {
+		if len(pkg.Errors) > 0 {
+			c.errs[pkg.PkgPath] = errors.New(pkg.Errors[0].Msg)
+			continue
+		}
+		c.pkgs[pkg.PkgPath] = pkg.Types
+	}
+
+	return nil
+}
+
+// Import implements types.Importer.
+func (c *checker) Import(path string) (*types.Package, error) {
+	if pkg, ok := c.pkgs[path]; ok {
+		return pkg, nil
+	}
+	if err, ok := c.errs[path]; ok {
+		return nil, err
+	}
+	if path == "unsafe" {
+		return types.Unsafe, nil
+	}
+	return importer.Default().Import(path)
+}
+
+// parse parses a wrapped snippet, returning any syntax errors as problems.
+func (c *checker) parse(name, src string) (*ast.File, []Problem) {
+	f, err := parser.ParseFile(c.fset, name, src, parser.ParseComments|parser.SkipObjectResolution)
+	if err == nil {
+		return f, nil
+	}
+
+	var list scanner.ErrorList
+	if !errors.As(err, &list) {
+		return nil, []Problem{{Msg: err.Error()}}
+	}
+
+	problems := make([]Problem, 0, len(list))
+	for _, e := range list {
+		problems = append(problems, Problem{Pos: e.Pos, Msg: e.Msg})
+	}
+	return nil, problems
+}
+
+// check type-checks f, ignoring the errors that are an artifact of the
+// snippet being partial rather than a sign of API drift.
+func (c *checker) check(f *ast.File) []Problem {
+	var problems []Problem
+	conf := types.Config{
+		Importer: c,
+		Error: func(err error) {
+			var terr types.Error
+			if !errors.As(err, &terr) || tolerated(terr) {
+				return
+			}
+			problems = append(problems, Problem{Pos: c.fset.Position(terr.Pos), Msg: terr.Msg})
+		},
+	}
+	_, _ = conf.Check(f.Name.Name, c.fset, []*ast.File{f}, nil)
+
+	return problems
+}
+
+// tolerated reports whether err is expected from a partial snippet: unused
+// variables and imports, placeholders for the reader's own code, and
+// alternative versions of the same statement shown side by side.
+func tolerated(err types.Error) bool {
+	if err.Soft {
+		return true
+	}
+	if strings.HasPrefix(err.Msg, "no new variables on left side of :=") {
+		return true
+	}
+	if m := placeholderRe.FindStringSubmatch(err.Msg); m != nil {
+		_, isPackage := knownImports[m[1]]
+		return !isPackage
+	}
+	return false
+}
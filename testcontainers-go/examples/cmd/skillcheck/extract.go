@@ -0,0 +1,110 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// skipDirective, placed on the line right before a fence, excludes the
+// following block from the check. Use it sparingly, for pseudo-code only.
+const skipDirective = "<!-- skillcheck:skip -->"
+
+// dirDirectivePrefix, followed by a directory and " -->" on the line right
+// before a fence, checks the following block against the Go module in that
+// directory instead of the current one, for snippets using modules kept out
+// of it, such as modules/compose:
+//
+//	<!-- skillcheck:dir compose -->
+const dirDirectivePrefix = "<!-- skillcheck:dir "
+
+// Snippet is a single ```go fenced block found in a Markdown document.
+type Snippet struct {
+	// Line is the 1-based line of the first line of code, right after the fence.
+	Line int
+	// Source is the code inside the fence, without the fence lines.
+	Source string
+	// Skip reports whether the block was marked with skipDirective.
+	Skip bool
+	// Dir is the directory of the module the block is checked against,
+	// relative to the current one, as set with dirDirectivePrefix. It is
+	// empty for the current module.
+	Dir string
+}
+
+// extractSnippets returns every ```go fenced block in r, in document order.
+func extractSnippets(r io.Reader) ([]Snippet, error) {
+	var (
+		snippets []Snippet
+		current  *Snippet
+		body     strings.Builder
+		prev     string
+		lineNo   int
+		inOther  bool // inside a fence for another language
+	)
+
+	sc := bufio.NewScanner(r)
+	for sc.Scan() {
+		lineNo++
+		line := sc.Text()
+		trimmed := strings.TrimSpace(line)
+
+		switch {
+		case inOther:
+			inOther = !strings.HasPrefix(trimmed, "```")
+		case current == nil && isGoFence(trimmed):
+			current = &Snippet{
+				Line: lineNo + 1,
+				Skip: strings.TrimSpace(prev) == skipDirective,
+				Dir:  dirDirective(prev),
+			}
+			body.Reset()
+		case current != nil && trimmed == "```":
+			current.Source = body.String()
+			snippets = append(snippets, *current)
+			current = nil
+		case current != nil:
+			body.WriteString(line)
+			body.WriteByte('\n')
+		case strings.HasPrefix(trimmed, "```"):
+			inOther = true
+		}
+
+		prev = line
+	}
+	if err := sc.Err(); err != nil {
+		return nil, fmt.Errorf("scan markdown: %w", err)
+	}
+
+	if current != nil {
+		return nil, fmt.Errorf("line %d: unterminated ```go block", current.Line-1)
+	}
+
+	return snippets, nil
+}
+
+// dirDirective returns the directory named by line if it is a
+// dirDirectivePrefix directive, or "" otherwise.
+func dirDirective(line string) string {
+	dir, ok := strings.CutPrefix(strings.TrimSpace(line), dirDirectivePrefix)
+	if !ok {
+		return ""
+	}
+	dir, ok = strings.CutSuffix(dir, "-->")
+	if !ok {
+		return ""
+	}
+	return strings.TrimSpace(dir)
+}
+
+// isGoFence reports whether line opens a Go code block.
+func isGoFence(line string) bool {
+	info, ok := strings.CutPrefix(line, "```")
+	if !ok {
+		return false
+	}
+
+	fields := strings.Fields(info)
+	return len(fields) > 0 && fields[0] == "go"
+}
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestExtractSnippets(t *testing.T) {
+	doc := "# Title\n" +
+		"\n" +
+		"```go\n" +
+		"ctx := context.Background()\n" +
+		"```\n" +
+		"\n" +
+		"```bash\n" +
+		"go test ./...\n" +
+		"```\n" +
+		"\n" +
+		"<!-- skillcheck:skip -->\n" +
+		"```go\n" +
+		"ctr := ...\n" +
+		"```\n" +
+		"\n" +
+		"<!-- skillcheck:dir compose -->\n" +
+		"```go\n" +
+		"stack, err := compose.NewDockerCompose(\"docker-compose.yml\")\n" +
+		"```\n"
+
+	snippets, err := extractSnippets(strings.NewReader(doc))
+	require.NoError(t, err)
+	require.Len(t, snippets, 3)
+
+	require.Equal(t, 4, snippets[0].Line)
+	require.Equal(t, "ctx := context.Background()\n", snippets[0].Source)
+	require.False(t, snippets[0].Skip)
+	require.Empty(t, snippets[0].Dir)
+
+	require.Equal(t, 13, snippets[1].Line)
+	require.True(t, snippets[1].Skip)
+
+	require.Equal(t, "compose", snippets[2].Dir)
+	require.False(t, snippets[2].Skip)
+}
+
+func TestExtractSnippetsUnterminated(t *testing.T) {
+	_, err := extractSnippets(strings.NewReader("```go\nfunc f() {}\n"))
+	require.ErrorContains(t, err, "unterminated")
+}
@@ -0,0 +1,130 @@
+// Command skillcheck verifies that the Go snippets in SKILL.md compile.
+//
+// It extracts every ```go fenced block from the document, wraps partial
+// snippets into complete files and type-checks them against the module
+// versions pinned in the go.mod of the current directory, so that the
+// documented examples cannot silently drift from the real API.
+//
+// Usage, from the examples directory:
+//
+//	go run ./cmd/skillcheck ../SKILL.md
+//
+// Blocks that are deliberately not valid Go can be excluded by placing
+// <!-- skillcheck:skip --> on the line right before the fence. Blocks using
+// a module with its own go.mod, such as the compose example, are checked
+// against it with <!-- skillcheck:dir compose --> instead.
+package main
+
+import (
+	"cmp"
+	"flag"
+	"fmt"
+	"go/ast"
+	"log"
+	"os"
+)
+
+func main() {
+	verbose := flag.Bool("v", false, "print the wrapped source of failing snippets")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "usage: skillcheck [-v] [SKILL.md]\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	path := "../SKILL.md"
+	if flag.NArg() > 0 {
+		path = flag.Arg(0)
+	}
+
+	failed, err := run(path, *verbose)
+	if err != nil {
+		log.Fatalf("skillcheck: %v", err)
+	}
+	if failed > 0 {
+		fmt.Fprintf(os.Stderr, "skillcheck: %d snippet(s) in %s do not compile\n", failed, path)
+		os.Exit(1)
+	}
+}
+
+// run checks the snippets in the Markdown file at path and returns the
+// number of snippets with problems.
+func run(path string, verbose bool) (int, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+
+	snippets, err := extractSnippets(f)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", path, err)
+	}
+
+	type parsed struct {
+		snippet  Snippet
+		checker  *checker
+		src      string
+		file     *ast.File
+		problems []Problem
+	}
+
+	var (
+		all      []parsed
+		checkers = make(map[string]*checker)
+		files    = make(map[*checker][]*ast.File)
+	)
+	for i, s := range snippets {
+		if s.Skip {
+			continue
+		}
+
+		src, err := wrapSnippet(path, s)
+		if err != nil {
+			return 0, fmt.Errorf("%s:%d: %w", path, s.Line, err)
+		}
+
+		// One checker per module, so that each module graph is loaded once
+		dir := cmp.Or(s.Dir, ".")
+		c, ok := checkers[dir]
+		if !ok {
+			c = newChecker(dir)
+			checkers[dir] = c
+		}
+
+		file, problems := c.parse(fmt.Sprintf("snippet%d.go", i), src)
+		if file != nil {
+			files[c] = append(files[c], file)
+		}
+		all = append(all, parsed{snippet: s, checker: c, src: src, file: file, problems: problems})
+	}
+
+	for c, fs := range files {
+		if err := c.load(fs); err != nil {
+			return 0, fmt.Errorf("%s: %w", c.dir, err)
+		}
+	}
+
+	failed := 0
+	for _, p := range all {
+		problems := p.problems
+		if p.file != nil {
+			problems = p.checker.check(p.file)
+		}
+		if len(problems) == 0 {
+			continue
+		}
+
+		failed++
+		for _, problem := range problems {
+			fmt.Println(problem)
+		}
+		if verbose {
+			fmt.Printf("--- wrapped snippet at %s:%d ---\n%s\n", path, p.snippet.Line, p.src)
+		}
+	}
+
+	fmt.Printf("skillcheck: checked %d snippet(s) in %s\n", len(all), path)
+
+	return failed, nil
+}
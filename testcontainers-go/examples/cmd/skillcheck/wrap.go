@@ -0,0 +1,252 @@
+package main
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// knownImports maps the package names used by partial snippets to their
+// import paths, so that snippets without an import block can be compiled.
+var knownImports = map[string]string{
+	"context":        "context",
+	"fmt":            "fmt",
+	"http":           "net/http",
+	"io":             "io",
+	"os":             "os",
+	"sql":            "database/sql",
+	"strings":        "strings",
+	"testing":        "testing",
+	"time":           "time",
+	"container":      "github.com/docker/docker/api/types/container",
+	"mount":          "github.com/docker/docker/api/types/mount",
+	"nat":            "github.com/docker/go-connections/nat",
+	"require":        "github.com/stretchr/testify/require",
+	"testcontainers": "github.com/testcontainers/testcontainers-go",
+	"exec":           "github.com/testcontainers/testcontainers-go/exec",
+	"network":        "github.com/testcontainers/testcontainers-go/network",
+	"wait":           "github.com/testcontainers/testcontainers-go/wait",
+	"compose":        "github.com/testcontainers/testcontainers-go/modules/compose",
+	"kafka":          "github.com/testcontainers/testcontainers-go/modules/kafka",
+	"postgres":       "github.com/testcontainers/testcontainers-go/modules/postgres",
+	"redis":          "github.com/testcontainers/testcontainers-go/modules/redis",
+}
+
+// fixtures are the variables partial snippets commonly assume to be in scope.
+// Snippets are wrapped in a nested block, so they can still redeclare them.
+var fixtures = []struct{ name, typ string }{
+	{"ctx", "context.Context"},
+	{"t", "*testing.T"},
+	{"ctr", "testcontainers.Container"},
+}
+
+// wrapSnippet turns a snippet into a complete Go source file. Snippets with a
+// package clause are used verbatim; anything else is split into top-level
+// declarations and statements, the latter wrapped in a function body with
+// the common fixtures in scope. The returned source carries //line
+// directives, so positions reported against it point back into the
+// Markdown document named filename.
+func wrapSnippet(filename string, s Snippet) (string, error) {
+	if hasPackageClause(s.Source) {
+		return lineDirective(filename, s.Line) + s.Source, nil
+	}
+
+	decls, stmts := splitSnippet(s.Source, s.Line)
+
+	var body strings.Builder
+	for _, c := range decls {
+		body.WriteString(lineDirective(filename, c.line))
+		body.WriteString(c.src)
+	}
+
+	if len(stmts) > 0 {
+		body.WriteString("\nfunc _() {\n")
+		for _, f := range fixtures {
+			fmt.Fprintf(&body, "\tvar %s %s\n", f.name, f.typ)
+		}
+		body.WriteString("\t{\n")
+		for _, c := range stmts {
+			body.WriteString(lineDirective(filename, c.line))
+			body.WriteString(c.src)
+		}
+		body.WriteString("\t}\n}\n")
+	}
+
+	imports, err := missingImports(body.String(), len(stmts) > 0)
+	if err != nil {
+		return "", err
+	}
+
+	var src strings.Builder
+	src.WriteString("package skillcheck\n\n")
+	for _, path := range imports {
+		src.WriteString(lineDirective(filename, s.Line))
+		fmt.Fprintf(&src, "import %q\n", path)
+	}
+	src.WriteString(body.String())
+
+	return src.String(), nil
+}
+
+// chunk is a run of consecutive snippet lines starting at line.
+type chunk struct {
+	line int
+	src  string
+}
+
+// splitSnippet separates top-level declarations (func, type and import
+// blocks starting in column 0) from the statements around them.
+func splitSnippet(src string, firstLine int) (decls, stmts []chunk) {
+	lines := strings.SplitAfter(src, "\n")
+
+	var (
+		cur    *chunk
+		inDecl bool
+		closer string
+	)
+	flush := func() {
+		if cur == nil || strings.TrimSpace(cur.src) == "" {
+			cur = nil
+			return
+		}
+		if inDecl {
+			decls = append(decls, *cur)
+		} else {
+			stmts = append(stmts, *cur)
+		}
+		cur = nil
+	}
+
+	for i, line := range lines {
+		trimmed := strings.TrimRight(line, "\n")
+
+		if !inDecl {
+			if c, ok := declCloser(trimmed); ok {
+				flush()
+				inDecl, closer = true, c
+			}
+		}
+
+		if cur == nil {
+			cur = &chunk{line: firstLine + i}
+		}
+		cur.src += line
+
+		if inDecl && (closer == "" || trimmed == closer) {
+			flush()
+			inDecl = false
+		}
+	}
+	flush()
+
+	return decls, stmts
+}
+
+// declCloser reports whether line opens a top-level declaration, returning
+// the line that closes it; an empty closer means a single-line declaration.
+func declCloser(line string) (string, bool) {
+	for _, kw := range []string{"func ", "type ", "import "} {
+		if !strings.HasPrefix(line, kw) {
+			continue
+		}
+		switch {
+		case strings.HasSuffix(line, "{"):
+			return "}", true
+		case strings.HasSuffix(line, "("):
+			return ")", true
+		default:
+			return "", true
+		}
+	}
+	return "", false
+}
+
+// missingImports returns the import paths for every known package name that
+// src references but does not import itself.
+func missingImports(src string, withFixtures bool) ([]string, error) {
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, "", "package skillcheck\n"+src, parser.SkipObjectResolution)
+	if err != nil {
+		// Report parse errors once the file is type-checked, with positions
+		// that point back into the Markdown document.
+		return nil, nil
+	}
+
+	imported := make(map[string]bool)
+	for _, spec := range f.Imports {
+		path, _ := strconv.Unquote(spec.Path.Value)
+		name := path[strings.LastIndex(path, "/")+1:]
+		if spec.Name != nil {
+			name = spec.Name.Name
+		}
+		imported[name] = true
+	}
+
+	// Declared identifiers shadow package names, e.g. a local "redis" client.
+	declared := make(map[string]bool)
+	ast.Inspect(f, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.AssignStmt:
+			if n.Tok == token.DEFINE {
+				for _, lhs := range n.Lhs {
+					if id, ok := lhs.(*ast.Ident); ok {
+						declared[id.Name] = true
+					}
+				}
+			}
+		case *ast.ValueSpec:
+			for _, id := range n.Names {
+				declared[id.Name] = true
+			}
+		}
+		return true
+	})
+
+	needed := make(map[string]bool)
+	if withFixtures {
+		needed["context"] = true
+		needed["testing"] = true
+		needed["testcontainers"] = true
+	}
+	ast.Inspect(f, func(n ast.Node) bool {
+		sel, ok := n.(*ast.SelectorExpr)
+		if !ok {
+			return true
+		}
+		if id, ok := sel.X.(*ast.Ident); ok && !declared[id.Name] {
+			needed[id.Name] = true
+		}
+		return true
+	})
+
+	var paths []string
+	for name := range needed {
+		path, ok := knownImports[name]
+		if ok && !imported[name] {
+			paths = append(paths, path)
+		}
+	}
+	sort.Strings(paths)
+
+	return paths, nil
+}
+
+// hasPackageClause reports whether src is a complete Go file.
+func hasPackageClause(src string) bool {
+	for _, line := range strings.Split(src, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "//") {
+			continue
+		}
+		return strings.HasPrefix(line, "package ")
+	}
+	return false
+}
+
+func lineDirective(filename string, line int) string {
+	return fmt.Sprintf("//line %s:%d\n", filename, line)
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"go/parser"
+	"go/token"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWrapSnippet(t *testing.T) {
+	t.Run("complete-file", func(t *testing.T) {
+		src := "package myapp_test\n\nfunc TestX(t *testing.T) {}\n"
+
+		wrapped, err := wrapSnippet("SKILL.md", Snippet{Line: 10, Source: src})
+		require.NoError(t, err)
+		require.Equal(t, "//line SKILL.md:10\n"+src, wrapped)
+	})
+
+	t.Run("statements", func(t *testing.T) {
+		src := "ctr, err := testcontainers.Run(ctx, \"nginx:alpine\")\n" +
+			"testcontainers.CleanupContainer(t, ctr)\n" +
+			"require.NoError(t, err)\n"
+
+		wrapped, err := wrapSnippet("SKILL.md", Snippet{Line: 20, Source: src})
+		require.NoError(t, err)
+		requireImports(t, wrapped,
+			"context",
+			"github.com/stretchr/testify/require",
+			"github.com/testcontainers/testcontainers-go",
+			"testing",
+		)
+		require.Contains(t, wrapped, "\tvar ctx context.Context\n")
+		require.Contains(t, wrapped, "//line SKILL.md:20\n"+src)
+	})
+
+	t.Run("declarations-and-statements", func(t *testing.T) {
+		src := "func TestX(t *testing.T) {\n" +
+			"\tt.Log(\"x\")\n" +
+			"}\n" +
+			"\n" +
+			"brokers, _ := kafkaContainer.Brokers(ctx)\n"
+
+		wrapped, err := wrapSnippet("SKILL.md", Snippet{Line: 1, Source: src})
+		require.NoError(t, err)
+		require.Contains(t, wrapped, "//line SKILL.md:1\nfunc TestX(t *testing.T) {\n")
+		require.Contains(t, wrapped, "//line SKILL.md:4\n\nbrokers, _ :=")
+		requireImports(t, wrapped, "context", "github.com/testcontainers/testcontainers-go", "testing")
+	})
+}
+
+func TestSplitSnippet(t *testing.T) {
+	src := "import \"github.com/testcontainers/testcontainers-go/network\"\n" +
+		"\n" +
+		"func TestA(t *testing.T) {\n" +
+		"}\n" +
+		"x := 1\n"
+
+	decls, stmts := splitSnippet(src, 5)
+	require.Len(t, decls, 2)
+	require.Equal(t, 5, decls[0].line)
+	require.Equal(t, 7, decls[1].line)
+	// The blank line between the declarations carries no statements.
+	require.Len(t, stmts, 1)
+	require.Equal(t, 9, stmts[0].line)
+}
+
+func requireImports(t *testing.T, src string, want ...string) {
+	t.Helper()
+
+	f, err := parser.ParseFile(token.NewFileSet(), "", src, parser.ImportsOnly)
+	require.NoError(t, err)
+
+	var got []string
+	for _, spec := range f.Imports {
+		got = append(got, spec.Path.Value[1:len(spec.Path.Value)-1])
+	}
+	require.ElementsMatch(t, want, got)
+}
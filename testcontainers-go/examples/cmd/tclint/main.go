@@ -0,0 +1,26 @@
+// Command tclint runs the testcontainers analyzers, which enforce the best
+// practices of the testcontainers-go skill in test code.
+//
+// Usage, from the examples directory:
+//
+//	go run ./cmd/tclint ./...
+//
+// Each analyzer can be enabled or disabled with its own flag, and -fix
+// applies the suggested fixes. Run with -help for the full list.
+package main
+
+import (
+	"golang.org/x/tools/go/analysis/multichecker"
+
+	"github.com/testcontainers/testcontainers-go/examples/analysis/tccleanup"
+	"github.com/testcontainers/testcontainers-go/examples/analysis/tcsleep"
+	"github.com/testcontainers/testcontainers-go/examples/analysis/tcwait"
+)
+
+func main() {
+	multichecker.Main(
+		tccleanup.Analyzer,
+		tcsleep.Analyzer,
+		tcwait.Analyzer,
+	)
+}
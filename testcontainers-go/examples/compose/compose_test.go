@@ -0,0 +1,150 @@
+package compose_test
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/api/types/volume"
+	_ "github.com/lib/pq"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/require"
+	"github.com/testcontainers/testcontainers-go"
+	"github.com/testcontainers/testcontainers-go/modules/compose"
+	"github.com/testcontainers/testcontainers-go/wait"
+)
+
+// TestComposeStack demonstrates the full lifecycle of a Docker Compose stack
+func TestComposeStack(t *testing.T) {
+	ctx := context.Background()
+
+	// Compose lowercases the identifier into the project name used in labels
+	project := fmt.Sprintf("examples-compose-%d", time.Now().UnixNano())
+
+	composeStack, err := compose.NewDockerComposeWith(
+		compose.WithStackFiles("testdata/docker-compose.yml"),
+		compose.StackIdentifier(project),
+	)
+	require.NoError(t, err)
+
+	// Register cleanup before Up, so that a partially started stack is removed
+	t.Cleanup(func() {
+		err := composeStack.Down(context.Background(), compose.RemoveOrphans(true), compose.RemoveVolumes(true))
+		require.NoError(t, err)
+	})
+
+	// Override the defaults of the compose file and wait for each service
+	// with a strategy matching what it provides
+	stack := composeStack.
+		WithEnv(map[string]string{
+			"POSTGRES_DB": "orders",
+			"GREETING":    "Hello from the test",
+		}).
+		WaitForService("postgres", wait.ForLog("database system is ready to accept connections").WithOccurrence(2)).
+		WaitForService("redis", wait.ForLog("Ready to accept connections")).
+		WaitForService("app", wait.ForHTTP("/health").WithPort("8080/tcp").WithStartupTimeout(2*time.Minute))
+
+	// Wait(true) also waits for the healthchecks declared in the compose file
+	err = stack.Up(ctx, compose.Wait(true))
+	require.NoError(t, err)
+
+	t.Run("Services", func(t *testing.T) {
+		require.ElementsMatch(t, []string{"app", "postgres", "redis"}, stack.Services())
+	})
+
+	t.Run("App", func(t *testing.T) {
+		appContainer, err := stack.ServiceContainer(ctx, "app")
+		require.NoError(t, err)
+
+		endpoint, err := appContainer.PortEndpoint(ctx, "8080/tcp", "http")
+		require.NoError(t, err)
+
+		// The app reaches its dependencies through the compose network
+		require.Equal(t, "ok\n", httpGet(t, endpoint+"/health"))
+
+		// The environment override replaced the default greeting
+		require.Equal(t, "Hello from the test", httpGet(t, endpoint+"/greeting"))
+	})
+
+	t.Run("Postgres", func(t *testing.T) {
+		postgresContainer, err := stack.ServiceContainer(ctx, "postgres")
+		require.NoError(t, err)
+
+		host, err := postgresContainer.Host(ctx)
+		require.NoError(t, err)
+
+		port, err := postgresContainer.MappedPort(ctx, "5432/tcp")
+		require.NoError(t, err)
+
+		connStr := fmt.Sprintf("postgres://app:app@%s:%s/orders?sslmode=disable", host, port.Port())
+		db, err := sql.Open("postgres", connStr)
+		require.NoError(t, err)
+		defer db.Close()
+
+		// The database name comes from the POSTGRES_DB override
+		var database string
+		err = db.QueryRow("SELECT current_database()").Scan(&database)
+		require.NoError(t, err)
+		require.Equal(t, "orders", database)
+	})
+
+	t.Run("Redis", func(t *testing.T) {
+		redisContainer, err := stack.ServiceContainer(ctx, "redis")
+		require.NoError(t, err)
+
+		endpoint, err := redisContainer.PortEndpoint(ctx, "6379/tcp", "")
+		require.NoError(t, err)
+
+		client := redis.NewClient(&redis.Options{Addr: endpoint})
+		defer client.Close()
+
+		err = client.Set(ctx, "stack", project, 0).Err()
+		require.NoError(t, err)
+
+		val, err := client.Get(ctx, "stack").Result()
+		require.NoError(t, err)
+		require.Equal(t, project, val)
+	})
+
+	t.Run("DownRemovesVolumes", func(t *testing.T) {
+		dockerClient, err := testcontainers.NewDockerClientWithOpts(ctx)
+		require.NoError(t, err)
+		defer dockerClient.Close()
+
+		projectVolumes := volume.ListOptions{
+			Filters: filters.NewArgs(filters.Arg("label", "com.docker.compose.project="+strings.ToLower(project))),
+		}
+
+		volumes, err := dockerClient.VolumeList(ctx, projectVolumes)
+		require.NoError(t, err)
+		require.Len(t, volumes.Volumes, 1, "the pgdata volume should exist while the stack is up")
+
+		err = stack.Down(ctx, compose.RemoveOrphans(true), compose.RemoveVolumes(true))
+		require.NoError(t, err)
+
+		volumes, err = dockerClient.VolumeList(ctx, projectVolumes)
+		require.NoError(t, err)
+		require.Empty(t, volumes.Volumes)
+	})
+}
+
+// httpGet returns the body of a successful GET request to url.
+func httpGet(t *testing.T, url string) string {
+	t.Helper()
+
+	resp, err := http.Get(url)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, resp.StatusCode, string(body))
+
+	return string(body)
+}
@@ -0,0 +1,38 @@
+// Command app is the tiny service of the compose example. It reports
+// whether its dependencies are reachable and echoes its configuration.
+package main
+
+import (
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"os"
+	"time"
+)
+
+func main() {
+	deps := map[string]string{
+		"postgres": os.Getenv("DATABASE_ADDR"),
+		"redis":    os.Getenv("REDIS_ADDR"),
+	}
+
+	http.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) {
+		for name, addr := range deps {
+			conn, err := net.DialTimeout("tcp", addr, time.Second)
+			if err != nil {
+				http.Error(w, fmt.Sprintf("%s unreachable: %v", name, err), http.StatusServiceUnavailable)
+				return
+			}
+			conn.Close()
+		}
+		fmt.Fprintln(w, "ok")
+	})
+
+	http.HandleFunc("/greeting", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, os.Getenv("GREETING"))
+	})
+
+	log.Println("listening on :8080")
+	log.Fatal(http.ListenAndServe(":8080", nil))
+}
@@ -0,0 +1,15 @@
+//go:build !fakecontainers
+
+package examples_test
+
+import (
+	"context"
+
+	"github.com/testcontainers/testcontainers-go"
+)
+
+// runContainer starts the tests that only rely on logs and exec, which can
+// also run against fake containers when built with -tags fakecontainers.
+func runContainer(ctx context.Context, img string, opts ...testcontainers.ContainerCustomizer) (testcontainers.Container, error) {
+	return testcontainers.Run(ctx, img, opts...)
+}
@@ -0,0 +1,20 @@
+//go:build fakecontainers
+
+package examples_test
+
+import (
+	"context"
+
+	"github.com/testcontainers/testcontainers-go"
+
+	"github.com/testcontainers/testcontainers-go/examples/internal/fakecontainer"
+)
+
+// runContainer starts a fake container running the command of the request
+// on the host, so that the logic of the tests using it can be checked
+// without Docker:
+//
+//	go test -tags fakecontainers -run 'TestGenericContainer(WithEnv|WithCommand|Logs|Exec|LogWait)$' .
+func runContainer(ctx context.Context, img string, opts ...testcontainers.ContainerCustomizer) (testcontainers.Container, error) {
+	return fakecontainer.Run(ctx, img, opts...)
+}
@@ -0,0 +1,161 @@
+// Package certs generates throwaway certificates for the TLS examples.
+//
+// A CA is created for each test and issues the certificates of the
+// servers, and of the clients when the servers check them. Nothing is
+// written to disk: the PEM blocks are copied into the containers with
+// testcontainers.WithFiles, and the clients trust the CA through Pool:
+//
+//	ca, err := certs.NewCA("examples CA")
+//	require.NoError(t, err)
+//
+//	server, err := ca.Issue("postgres", "localhost", "127.0.0.1")
+//	require.NoError(t, err)
+//
+//	ctr, err := postgres.Run(ctx, images.Postgres.Ref(),
+//		testcontainers.WithFiles(testcontainers.ContainerFile{
+//			Reader:            bytes.NewReader(server.CertPEM),
+//			ContainerFilePath: "/var/lib/postgresql/tls/server.crt",
+//			FileMode:          0o644,
+//		}),
+//	)
+//
+// The certificates are valid for a day, from an hour ago so that a Docker
+// VM whose clock lags behind the host still accepts them.
+package certs
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"net"
+	"time"
+)
+
+// The certificates are valid from notBefore, relative to now, for validity.
+const (
+	notBefore = -time.Hour
+	validity  = 24 * time.Hour
+)
+
+// CA is a certificate authority issuing certificates.
+type CA struct {
+	// CertPEM is the PEM-encoded certificate of the CA, for servers and
+	// clients to trust.
+	CertPEM []byte
+
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+}
+
+// Cert is a certificate issued by a CA, with its private key.
+type Cert struct {
+	// CertPEM is the PEM-encoded certificate.
+	CertPEM []byte
+
+	// KeyPEM is the PEM-encoded PKCS #8 private key.
+	KeyPEM []byte
+}
+
+// NewCA returns a new self-signed CA.
+func NewCA(commonName string) (*CA, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, fmt.Errorf("generate CA key: %w", err)
+	}
+
+	tmpl, err := template(commonName)
+	if err != nil {
+		return nil, err
+	}
+	tmpl.IsCA = true
+	tmpl.BasicConstraintsValid = true
+	tmpl.KeyUsage = x509.KeyUsageCertSign | x509.KeyUsageCRLSign
+
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		return nil, fmt.Errorf("create CA certificate: %w", err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		return nil, fmt.Errorf("parse CA certificate: %w", err)
+	}
+
+	return &CA{
+		CertPEM: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		cert:    cert,
+		key:     key,
+	}, nil
+}
+
+// Issue returns a certificate for hosts, which are IP addresses or DNS
+// names. It can authenticate both servers and clients; a client
+// certificate needs no hosts.
+func (ca *CA) Issue(commonName string, hosts ...string) (*Cert, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, fmt.Errorf("generate key: %w", err)
+	}
+
+	tmpl, err := template(commonName)
+	if err != nil {
+		return nil, err
+	}
+	tmpl.KeyUsage = x509.KeyUsageDigitalSignature
+	tmpl.ExtKeyUsage = []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth}
+	for _, h := range hosts {
+		if ip := net.ParseIP(h); ip != nil {
+			tmpl.IPAddresses = append(tmpl.IPAddresses, ip)
+		} else {
+			tmpl.DNSNames = append(tmpl.DNSNames, h)
+		}
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, ca.cert, &key.PublicKey, ca.key)
+	if err != nil {
+		return nil, fmt.Errorf("create certificate %s: %w", commonName, err)
+	}
+	keyDER, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		return nil, fmt.Errorf("marshal key: %w", err)
+	}
+
+	return &Cert{
+		CertPEM: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		KeyPEM:  pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: keyDER}),
+	}, nil
+}
+
+// Pool returns a pool holding the certificate of the CA, to use as the
+// RootCAs of a client or the ClientCAs of a server.
+func (ca *CA) Pool() *x509.CertPool {
+	pool := x509.NewCertPool()
+	pool.AddCert(ca.cert)
+	return pool
+}
+
+// TLSCertificate returns the certificate for tls.Config.Certificates.
+func (c *Cert) TLSCertificate() (tls.Certificate, error) {
+	return tls.X509KeyPair(c.CertPEM, c.KeyPEM)
+}
+
+// template returns the fields common to every certificate.
+func template(commonName string) (*x509.Certificate, error) {
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return nil, fmt.Errorf("generate serial number: %w", err)
+	}
+
+	now := time.Now()
+	return &x509.Certificate{
+		SerialNumber: serial,
+		Subject:      pkix.Name{CommonName: commonName},
+		NotBefore:    now.Add(notBefore),
+		NotAfter:     now.Add(notBefore + validity),
+	}, nil
+}
@@ -0,0 +1,103 @@
+package certs_test
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/testcontainers/testcontainers-go/examples/internal/certs"
+)
+
+// parse parses a PEM-encoded certificate.
+func parse(t *testing.T, data []byte) *x509.Certificate {
+	t.Helper()
+
+	block, rest := pem.Decode(data)
+	require.NotNil(t, block)
+	require.Empty(t, rest)
+	require.Equal(t, "CERTIFICATE", block.Type)
+
+	cert, err := x509.ParseCertificate(block.Bytes)
+	require.NoError(t, err)
+	return cert
+}
+
+func TestIssue(t *testing.T) {
+	ca, err := certs.NewCA("test CA")
+	require.NoError(t, err)
+
+	caCert := parse(t, ca.CertPEM)
+	require.True(t, caCert.IsCA)
+	require.Equal(t, "test CA", caCert.Subject.CommonName)
+
+	server, err := ca.Issue("server", "localhost", "127.0.0.1")
+	require.NoError(t, err)
+
+	cert := parse(t, server.CertPEM)
+	require.Equal(t, []string{"localhost"}, cert.DNSNames)
+	require.Len(t, cert.IPAddresses, 1)
+	require.True(t, cert.IPAddresses[0].Equal(net.IPv4(127, 0, 0, 1)))
+	require.True(t, cert.NotBefore.Before(time.Now().Add(-30*time.Minute)))
+
+	for _, name := range []string{"localhost", "127.0.0.1"} {
+		_, err = cert.Verify(x509.VerifyOptions{
+			DNSName: name,
+			Roots:   ca.Pool(),
+		})
+		require.NoError(t, err, name)
+	}
+
+	_, err = cert.Verify(x509.VerifyOptions{DNSName: "example.com", Roots: ca.Pool()})
+	require.ErrorAs(t, err, new(x509.HostnameError))
+
+	// Another CA does not trust it
+	other, err := certs.NewCA("other CA")
+	require.NoError(t, err)
+	_, err = cert.Verify(x509.VerifyOptions{DNSName: "localhost", Roots: other.Pool()})
+	require.ErrorAs(t, err, new(x509.UnknownAuthorityError))
+}
+
+func TestHandshake(t *testing.T) {
+	ca, err := certs.NewCA("test CA")
+	require.NoError(t, err)
+
+	server, err := ca.Issue("server", "db.example.com")
+	require.NoError(t, err)
+	client, err := ca.Issue("client")
+	require.NoError(t, err)
+
+	serverCert, err := server.TLSCertificate()
+	require.NoError(t, err)
+	clientCert, err := client.TLSCertificate()
+	require.NoError(t, err)
+
+	// Mutual TLS: each side checks the certificate of the other
+	serverConn, clientConn := net.Pipe()
+	defer serverConn.Close()
+	defer clientConn.Close()
+
+	srv := tls.Server(serverConn, &tls.Config{
+		Certificates: []tls.Certificate{serverCert},
+		ClientAuth:   tls.RequireAndVerifyClientCert,
+		ClientCAs:    ca.Pool(),
+	})
+	cli := tls.Client(clientConn, &tls.Config{
+		Certificates: []tls.Certificate{clientCert},
+		RootCAs:      ca.Pool(),
+		ServerName:   "db.example.com",
+	})
+
+	done := make(chan error, 1)
+	go func() {
+		done <- srv.Handshake()
+	}()
+
+	require.NoError(t, cli.Handshake())
+	require.NoError(t, <-done)
+	require.Equal(t, "client", srv.ConnectionState().PeerCertificates[0].Subject.CommonName)
+}
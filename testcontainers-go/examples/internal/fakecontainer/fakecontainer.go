@@ -0,0 +1,582 @@
+// Package fakecontainer provides a stand-in for testcontainers.Container
+// that runs without Docker.
+//
+// Run accepts the same customizers as testcontainers.Run, but instead of
+// starting the image it runs the entrypoint and command of the request as a
+// local process, and serves each exposed port from a net.Listener on the
+// loopback interface:
+//
+//	ctr, err := fakecontainer.Run(ctx, "alpine:latest",
+//		testcontainers.WithCmd("sh", "-c", "echo 'Running...'; sleep 300"),
+//		testcontainers.WithWaitStrategy(wait.ForLog("Running...")),
+//	)
+//	testcontainers.CleanupContainer(t, ctr)
+//	require.NoError(t, err)
+//
+// Logs returns the output of the process, which is also passed to the
+// consumers given with testcontainers.WithLogConsumers, and Exec runs
+// commands on the host with the environment of the request, so tests that
+// only rely on those behave as they do against a real container. The image
+// is never pulled, and there is no isolation: commands see the host file
+// system.
+//
+// Nothing listens on an exposed port unless WithHandler gives it an HTTP
+// handler. Wait strategies that read the logs, run commands or connect to
+// mapped ports work; wait.ForListeningPort must skip its internal check,
+// which looks for the unmapped port on the host.
+package fakecontainer
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/pkg/stdcopy"
+	"github.com/docker/go-connections/nat"
+	"github.com/testcontainers/testcontainers-go"
+	tcexec "github.com/testcontainers/testcontainers-go/exec"
+)
+
+// ErrUnsupported is returned by the Container methods that have no
+// meaningful fake implementation, such as copying files or inspecting
+// networks.
+var ErrUnsupported = errors.New("fakecontainer: not supported without Docker")
+
+// host is the address exposed ports are served on.
+const host = "127.0.0.1"
+
+// Compile-time check that Container is a drop-in replacement.
+var _ testcontainers.Container = (*Container)(nil)
+
+// Option configures the fake. It is a no-op when passed to testcontainers.Run,
+// so the same options can be given to both.
+type Option func(*options)
+
+type options struct {
+	handlers map[nat.Port]http.Handler
+}
+
+// Customize is a NOOP. It's defined to satisfy the testcontainers.ContainerCustomizer interface.
+func (o Option) Customize(*testcontainers.GenericContainerRequest) error {
+	return nil
+}
+
+// WithHandler serves h on the exposed port, standing in for the service the
+// image would run.
+func WithHandler(port string, h http.Handler) Option {
+	return func(o *options) {
+		o.handlers[normalizePort(port)] = h
+	}
+}
+
+// Container is a fake container backed by a local process and listeners.
+type Container struct {
+	id  string
+	req testcontainers.ContainerRequest
+
+	cmd  *exec.Cmd
+	logs *syncBuffer
+	done chan struct{}
+
+	listeners map[nat.Port]net.Listener
+	servers   []*http.Server
+
+	mu       sync.Mutex
+	exitCode int
+	running  bool
+}
+
+// Run starts a fake container for the request built from opts and waits
+// for it to be ready with the wait strategy of the request, if any. As with
+// testcontainers.Run, the returned container must be terminated even when
+// an error is returned.
+func Run(ctx context.Context, img string, opts ...testcontainers.ContainerCustomizer) (*Container, error) {
+	req := testcontainers.GenericContainerRequest{
+		ContainerRequest: testcontainers.ContainerRequest{Image: img},
+		Started:          true,
+	}
+	settings := options{handlers: make(map[nat.Port]http.Handler)}
+
+	for _, opt := range opts {
+		if o, ok := opt.(Option); ok {
+			o(&settings)
+		}
+		if err := opt.Customize(&req); err != nil {
+			return nil, fmt.Errorf("customize: %w", err)
+		}
+	}
+
+	c := &Container{
+		id:        newID(),
+		req:       req.ContainerRequest,
+		logs:      &syncBuffer{},
+		done:      make(chan struct{}),
+		listeners: make(map[nat.Port]net.Listener),
+	}
+
+	if err := c.listen(settings.handlers); err != nil {
+		return c, err
+	}
+
+	if !req.Started {
+		return c, nil
+	}
+
+	if err := c.Start(ctx); err != nil {
+		return c, err
+	}
+
+	if c.req.WaitingFor != nil {
+		if err := c.req.WaitingFor.WaitUntilReady(ctx, c); err != nil {
+			return c, fmt.Errorf("wait until ready: %w", err)
+		}
+	}
+
+	return c, nil
+}
+
+// listen opens a listener for every exposed port, serving the handler
+// registered for it, if any.
+func (c *Container) listen(handlers map[nat.Port]http.Handler) error {
+	for _, p := range c.req.ExposedPorts {
+		port := normalizePort(p)
+
+		ln, err := net.Listen("tcp", net.JoinHostPort(host, "0"))
+		if err != nil {
+			return fmt.Errorf("listen for port %s: %w", port, err)
+		}
+		c.listeners[port] = ln
+
+		h, ok := handlers[port]
+		if !ok {
+			continue
+		}
+		srv := &http.Server{Handler: h, ReadHeaderTimeout: 10 * time.Second}
+		c.servers = append(c.servers, srv)
+		go func() {
+			_ = srv.Serve(ln)
+		}()
+	}
+	return nil
+}
+
+// Start runs the entrypoint and command of the request as a local process.
+// A request without either stays running until terminated.
+func (c *Container) Start(context.Context) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.running {
+		return nil
+	}
+
+	args := append(append([]string{}, c.req.Entrypoint...), c.req.Cmd...)
+	c.done = make(chan struct{})
+	c.running = true
+
+	if len(args) == 0 {
+		return nil
+	}
+
+	// The process must outlive the context of Run, as a container does.
+	cmd := exec.Command(args[0], args[1:]...)
+	cmd.Env = c.environ()
+	cmd.Stdout = c.logs
+	cmd.Stderr = c.logs
+	if cfg := c.req.LogConsumerCfg; cfg != nil && len(cfg.Consumers) > 0 {
+		mu := &sync.Mutex{}
+		cmd.Stdout = io.MultiWriter(c.logs, logWriter{mu: mu, logType: testcontainers.StdoutLog, consumers: cfg.Consumers})
+		cmd.Stderr = io.MultiWriter(c.logs, logWriter{mu: mu, logType: testcontainers.StderrLog, consumers: cfg.Consumers})
+	}
+	setProcessGroup(cmd)
+
+	if err := cmd.Start(); err != nil {
+		c.running = false
+		return fmt.Errorf("start %q: %w", args[0], err)
+	}
+	c.cmd = cmd
+
+	done := c.done
+	go func() {
+		err := cmd.Wait()
+
+		c.mu.Lock()
+		defer c.mu.Unlock()
+
+		c.running = false
+		c.exitCode = cmd.ProcessState.ExitCode()
+		if err != nil && c.exitCode == 0 {
+			c.exitCode = 1
+		}
+		close(done)
+	}()
+
+	return nil
+}
+
+// Stop kills the process, waiting for it to exit.
+func (c *Container) Stop(_ context.Context, timeout *time.Duration) error {
+	c.mu.Lock()
+	cmd, done, running := c.cmd, c.done, c.running
+	if cmd == nil && running {
+		// Nothing runs in the container: it stops straight away.
+		c.running = false
+		close(done)
+	}
+	c.mu.Unlock()
+
+	if cmd == nil || !running {
+		return nil
+	}
+
+	if err := killProcessGroup(cmd); err != nil && !errors.Is(err, os.ErrProcessDone) {
+		return fmt.Errorf("kill: %w", err)
+	}
+
+	wait := 10 * time.Second
+	if timeout != nil {
+		wait = *timeout
+	}
+	select {
+	case <-done:
+		return nil
+	case <-time.After(wait):
+		return errors.New("fakecontainer: process did not exit")
+	}
+}
+
+// Terminate stops the process and closes the listeners.
+func (c *Container) Terminate(ctx context.Context, _ ...testcontainers.TerminateOption) error {
+	errs := []error{c.Stop(ctx, nil)}
+
+	for _, srv := range c.servers {
+		errs = append(errs, srv.Close())
+	}
+	for _, ln := range c.listeners {
+		if err := ln.Close(); err != nil && !errors.Is(err, net.ErrClosed) {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// Exec runs cmd on the host with the environment of the container. As with
+// Docker, the output is multiplexed unless tcexec.Multiplexed is given.
+func (c *Container) Exec(ctx context.Context, cmd []string, options ...tcexec.ProcessOption) (int, io.Reader, error) {
+	if len(cmd) == 0 {
+		return 0, nil, errors.New("fakecontainer: empty command")
+	}
+
+	processOptions := tcexec.NewProcessOptions(cmd)
+	for _, o := range options {
+		o.Apply(processOptions)
+	}
+	cfg := processOptions.ExecConfig
+
+	// stdout and stderr are copied by separate goroutines.
+	var out syncBuffer
+	proc := exec.CommandContext(ctx, cfg.Cmd[0], cfg.Cmd[1:]...)
+	proc.Env = append(c.environ(), cfg.Env...)
+	proc.Dir = cfg.WorkingDir
+	if proc.Dir == "" {
+		proc.Dir = "/"
+	}
+	if cfg.AttachStdout {
+		proc.Stdout = stdcopy.NewStdWriter(&out, stdcopy.Stdout)
+	}
+	if cfg.AttachStderr {
+		proc.Stderr = stdcopy.NewStdWriter(&out, stdcopy.Stderr)
+	}
+
+	err := proc.Run()
+	var exitErr *exec.ExitError
+	switch {
+	case errors.As(err, &exitErr):
+		// A failing command is reported by its exit code, as with Docker.
+	case errors.Is(err, exec.ErrNotFound):
+		// The code Docker reports for a missing executable.
+		return 126, bytes.NewReader(out.Bytes()), nil
+	case err != nil:
+		return 0, nil, fmt.Errorf("exec %q: %w", cfg.Cmd[0], err)
+	}
+
+	processOptions.Reader = bytes.NewReader(out.Bytes())
+	for _, o := range options {
+		o.Apply(processOptions)
+	}
+
+	return proc.ProcessState.ExitCode(), processOptions.Reader, nil
+}
+
+// environ returns the host environment extended with the request's.
+func (c *Container) environ() []string {
+	env := os.Environ()
+	for k, v := range c.req.Env {
+		env = append(env, k+"="+v)
+	}
+	return env
+}
+
+// logWriter passes each write of the process to the log consumers of the
+// request, as Docker passes each frame of the log stream. The mutex is
+// shared by stdout and stderr, so consumers are never called concurrently.
+type logWriter struct {
+	mu        *sync.Mutex
+	logType   string
+	consumers []testcontainers.LogConsumer
+}
+
+func (w logWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	content := bytes.Clone(p)
+	for _, consumer := range w.consumers {
+		consumer.Accept(testcontainers.Log{LogType: w.logType, Content: content})
+	}
+	return len(p), nil
+}
+
+// Logs returns the output of the process so far.
+func (c *Container) Logs(context.Context) (io.ReadCloser, error) {
+	return io.NopCloser(bytes.NewReader(c.logs.Bytes())), nil
+}
+
+// GetContainerID returns the random ID of the fake.
+func (c *Container) GetContainerID() string {
+	return c.id
+}
+
+// SessionID returns the testcontainers session ID.
+func (c *Container) SessionID() string {
+	return testcontainers.SessionID()
+}
+
+// Host returns the loopback address exposed ports are served on.
+func (c *Container) Host(context.Context) (string, error) {
+	return host, nil
+}
+
+// MappedPort returns the port of the listener standing in for port.
+func (c *Container) MappedPort(_ context.Context, port nat.Port) (nat.Port, error) {
+	ln, ok := c.listeners[normalizePort(string(port))]
+	if !ok {
+		return "", fmt.Errorf("port %q not found", port)
+	}
+	return nat.NewPort("tcp", strconv.Itoa(ln.Addr().(*net.TCPAddr).Port))
+}
+
+// PortEndpoint returns proto://host:port for the exposed port.
+func (c *Container) PortEndpoint(ctx context.Context, port nat.Port, proto string) (string, error) {
+	mapped, err := c.MappedPort(ctx, port)
+	if err != nil {
+		return "", err
+	}
+
+	endpoint := net.JoinHostPort(host, mapped.Port())
+	if proto != "" {
+		endpoint = proto + "://" + endpoint
+	}
+	return endpoint, nil
+}
+
+// Endpoint returns proto://host:port for the lowest exposed port.
+func (c *Container) Endpoint(ctx context.Context, proto string) (string, error) {
+	var lowest nat.Port
+	for port := range c.listeners {
+		if lowest == "" || port.Int() < lowest.Int() {
+			lowest = port
+		}
+	}
+	if lowest == "" {
+		return "", errors.New("no ports exposed")
+	}
+	return c.PortEndpoint(ctx, lowest, proto)
+}
+
+// Ports returns the mapping of the exposed ports.
+func (c *Container) Ports(ctx context.Context) (nat.PortMap, error) {
+	ports := make(nat.PortMap, len(c.listeners))
+	for port := range c.listeners {
+		mapped, err := c.MappedPort(ctx, port)
+		if err != nil {
+			return nil, err
+		}
+		ports[port] = []nat.PortBinding{{HostIP: host, HostPort: mapped.Port()}}
+	}
+	return ports, nil
+}
+
+// Inspect returns the little the fake knows about itself: its ID, image,
+// configuration, state and ports.
+func (c *Container) Inspect(ctx context.Context) (*container.InspectResponse, error) {
+	state, err := c.State(ctx)
+	if err != nil {
+		return nil, err
+	}
+	ports, err := c.Ports(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	exposed := make(nat.PortSet, len(ports))
+	env := make([]string, 0, len(c.req.Env))
+	for port := range ports {
+		exposed[port] = struct{}{}
+	}
+	for k, v := range c.req.Env {
+		env = append(env, k+"="+v)
+	}
+
+	resp := &container.InspectResponse{
+		ContainerJSONBase: &container.ContainerJSONBase{
+			ID:    c.id,
+			Name:  "/" + c.req.Name,
+			Image: c.req.Image,
+			State: state,
+		},
+		Config: &container.Config{
+			Image:        c.req.Image,
+			Env:          env,
+			Cmd:          c.req.Cmd,
+			Entrypoint:   c.req.Entrypoint,
+			Labels:       c.req.Labels,
+			ExposedPorts: exposed,
+		},
+		NetworkSettings: &container.NetworkSettings{},
+	}
+	resp.NetworkSettings.Ports = ports
+	return resp, nil
+}
+
+// State reports whether the process is running, or how it exited.
+func (c *Container) State(context.Context) (*container.State, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.running {
+		return &container.State{Status: container.StateRunning, Running: true}, nil
+	}
+	return &container.State{Status: container.StateExited, ExitCode: c.exitCode}, nil
+}
+
+// IsRunning reports whether the process is running.
+func (c *Container) IsRunning() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.running
+}
+
+// Name returns the name of the request.
+func (c *Container) Name(context.Context) (string, error) {
+	return "/" + c.req.Name, nil
+}
+
+// Networks returns no network: the fake is only reachable from the host.
+func (c *Container) Networks(context.Context) ([]string, error) {
+	return nil, nil
+}
+
+// NetworkAliases returns no alias: the fake is only reachable from the host.
+func (c *Container) NetworkAliases(context.Context) (map[string][]string, error) {
+	return map[string][]string{}, nil
+}
+
+// ContainerIP returns the loopback address.
+func (c *Container) ContainerIP(context.Context) (string, error) {
+	return host, nil
+}
+
+// ContainerIPs returns the loopback address.
+func (c *Container) ContainerIPs(context.Context) ([]string, error) {
+	return []string{host}, nil
+}
+
+// CopyToContainer is not supported.
+func (c *Container) CopyToContainer(context.Context, []byte, string, int64) error {
+	return ErrUnsupported
+}
+
+// CopyDirToContainer is not supported.
+func (c *Container) CopyDirToContainer(context.Context, string, string, int64) error {
+	return ErrUnsupported
+}
+
+// CopyFileToContainer is not supported.
+func (c *Container) CopyFileToContainer(context.Context, string, string, int64) error {
+	return ErrUnsupported
+}
+
+// CopyFileFromContainer is not supported.
+func (c *Container) CopyFileFromContainer(context.Context, string) (io.ReadCloser, error) {
+	return nil, ErrUnsupported
+}
+
+// FollowOutput is not supported: use testcontainers.WithLogConsumers.
+func (c *Container) FollowOutput(testcontainers.LogConsumer) {}
+
+// StartLogProducer is not supported.
+func (c *Container) StartLogProducer(context.Context, ...testcontainers.LogProductionOption) error {
+	return ErrUnsupported
+}
+
+// StopLogProducer is a no-op.
+func (c *Container) StopLogProducer() error {
+	return nil
+}
+
+// GetLogProductionErrorChannel returns nil, as no log producer ever runs.
+func (c *Container) GetLogProductionErrorChannel() <-chan error {
+	return nil
+}
+
+// newID returns a random ID in the format of Docker's.
+func newID() string {
+	b := make([]byte, 32)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// normalizePort defaults the protocol of port to tcp, as Docker does.
+func normalizePort(port string) nat.Port {
+	if !strings.Contains(port, "/") {
+		port += "/tcp"
+	}
+	return nat.Port(port)
+}
+
+// syncBuffer is a bytes.Buffer safe for concurrent writes by the stdout
+// and stderr of a process, and reads by Logs.
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) Bytes() []byte {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return bytes.Clone(b.buf.Bytes())
+}
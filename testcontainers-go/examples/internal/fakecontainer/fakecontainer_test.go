@@ -0,0 +1,141 @@
+package fakecontainer_test
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/testcontainers/testcontainers-go"
+	"github.com/testcontainers/testcontainers-go/exec"
+	"github.com/testcontainers/testcontainers-go/wait"
+
+	"github.com/testcontainers/testcontainers-go/examples/internal/fakecontainer"
+)
+
+func TestRunWaitsForLog(t *testing.T) {
+	ctx := context.Background()
+
+	ctr, err := fakecontainer.Run(ctx, "alpine:latest",
+		testcontainers.WithCmd("sh", "-c", "echo 'Starting...'; sleep 1; echo 'Running...'; sleep 300"),
+		testcontainers.WithWaitStrategy(wait.ForLog("Running...").WithStartupTimeout(10*time.Second)),
+	)
+	testcontainers.CleanupContainer(t, ctr)
+	require.NoError(t, err)
+
+	logs, err := ctr.Logs(ctx)
+	require.NoError(t, err)
+	defer logs.Close()
+
+	content, err := io.ReadAll(logs)
+	require.NoError(t, err)
+	require.Equal(t, "Starting...\nRunning...\n", string(content))
+
+	state, err := ctr.State(ctx)
+	require.NoError(t, err)
+	require.True(t, state.Running)
+}
+
+func TestRunFailingCommand(t *testing.T) {
+	ctx := context.Background()
+
+	ctr, err := fakecontainer.Run(ctx, "alpine:latest",
+		testcontainers.WithCmd("sh", "-c", "exit 3"),
+		testcontainers.WithWaitStrategy(wait.ForLog("never").WithStartupTimeout(5*time.Second)),
+	)
+	testcontainers.CleanupContainer(t, ctr)
+	require.ErrorContains(t, err, "container exited with code 3")
+}
+
+func TestExec(t *testing.T) {
+	ctx := context.Background()
+
+	ctr, err := fakecontainer.Run(ctx, "alpine:latest",
+		testcontainers.WithEnv(map[string]string{"MY_VAR": "test_value"}),
+	)
+	testcontainers.CleanupContainer(t, ctr)
+	require.NoError(t, err)
+
+	t.Run("multiplexed", func(t *testing.T) {
+		exitCode, reader, err := ctr.Exec(ctx, []string{"sh", "-c", "echo $MY_VAR; echo oops >&2"}, exec.Multiplexed())
+		require.NoError(t, err)
+		require.Equal(t, 0, exitCode)
+
+		output, err := io.ReadAll(reader)
+		require.NoError(t, err)
+		require.Equal(t, "test_value\noops\n", string(output))
+	})
+
+	t.Run("raw", func(t *testing.T) {
+		_, reader, err := ctr.Exec(ctx, []string{"echo", "hello"})
+		require.NoError(t, err)
+
+		output, err := io.ReadAll(reader)
+		require.NoError(t, err)
+		// Without exec.Multiplexed the stream keeps Docker's 8-byte headers.
+		require.Equal(t, "\x01\x00\x00\x00\x00\x00\x00\x06hello\n", string(output))
+	})
+
+	t.Run("working directory", func(t *testing.T) {
+		_, reader, err := ctr.Exec(ctx, []string{"pwd"}, exec.Multiplexed())
+		require.NoError(t, err)
+
+		output, err := io.ReadAll(reader)
+		require.NoError(t, err)
+		require.Equal(t, "/\n", string(output))
+	})
+
+	t.Run("exit code", func(t *testing.T) {
+		exitCode, _, err := ctr.Exec(ctx, []string{"sh", "-c", "exit 2"})
+		require.NoError(t, err)
+		require.Equal(t, 2, exitCode)
+	})
+}
+
+func TestHandler(t *testing.T) {
+	ctx := context.Background()
+
+	ctr, err := fakecontainer.Run(ctx, "nginx:alpine",
+		testcontainers.WithExposedPorts("80/tcp", "443"),
+		fakecontainer.WithHandler("80", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprint(w, "Hello from fake nginx")
+		})),
+		testcontainers.WithWaitStrategy(wait.ForHTTP("/").WithPort("80/tcp").WithStartupTimeout(10*time.Second)),
+	)
+	testcontainers.CleanupContainer(t, ctr)
+	require.NoError(t, err)
+
+	endpoint, err := ctr.Endpoint(ctx, "http")
+	require.NoError(t, err)
+
+	resp, err := http.Get(endpoint)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	require.Equal(t, "Hello from fake nginx", string(body))
+
+	ports, err := ctr.Ports(ctx)
+	require.NoError(t, err)
+	require.Len(t, ports, 2)
+}
+
+func TestTerminate(t *testing.T) {
+	ctx := context.Background()
+
+	ctr, err := fakecontainer.Run(ctx, "alpine:latest", testcontainers.WithCmd("sleep", "300"))
+	testcontainers.CleanupContainer(t, ctr)
+	require.NoError(t, err)
+	require.True(t, ctr.IsRunning())
+
+	require.NoError(t, ctr.Terminate(ctx))
+	require.False(t, ctr.IsRunning())
+
+	state, err := ctr.State(ctx)
+	require.NoError(t, err)
+	require.Equal(t, "exited", state.Status)
+}
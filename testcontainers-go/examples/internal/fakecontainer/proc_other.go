@@ -0,0 +1,13 @@
+//go:build !unix
+
+package fakecontainer
+
+import "os/exec"
+
+// setProcessGroup is a no-op: only the process itself is killed.
+func setProcessGroup(*exec.Cmd) {}
+
+// killProcessGroup kills the process started by cmd.
+func killProcessGroup(cmd *exec.Cmd) error {
+	return cmd.Process.Kill()
+}
@@ -0,0 +1,323 @@
+// Package images is the catalog of the container images used by the
+// examples.
+//
+// Tests never spell out an image reference. They name an Image of the
+// catalog and pull whatever its Ref resolves to:
+//
+//	pgContainer, err := postgres.Run(ctx, images.Postgres.Ref(),
+//		postgres.BasicWaitStrategies(),
+//	)
+//
+// Ref starts from the reference of the catalog and applies, in order:
+//
+//  1. The overrides of the EXAMPLES_IMAGE_<REPOSITORY> variables, then
+//     those of the file named by EXAMPLES_IMAGES_FILE.
+//  2. The substitutors registered with Use.
+//
+// Ref leaves out the Docker Hub prefix of testcontainers, from
+// TESTCONTAINERS_HUB_IMAGE_NAME_PREFIX or hub.image.name.prefix in
+// ~/.testcontainers.properties, since testcontainers prepends it itself to
+// the images it runs that are still on Docker Hub. Pulled includes it, for
+// logs and for references testcontainers never sees, such as the base
+// images given to a Dockerfile as build arguments.
+//
+// An override replaces the repository of an image, and keeps its tag unless
+// it gives one. <REPOSITORY> is the repository in upper case, with every
+// character other than a letter or a digit replaced by an underscore:
+//
+//	# Every redis image, whatever its tag, comes from the mirror
+//	EXAMPLES_IMAGE_REDIS=mirror.example.com/library/redis
+//
+//	# The Kafka image is replaced, tag included
+//	EXAMPLES_IMAGE_CONFLUENTINC_CONFLUENT_LOCAL=mirror.example.com/kafka:7.5.0
+//
+// The overrides file has one override per line, keyed by a repository or by
+// a full reference. Blank lines and lines starting with # are ignored:
+//
+//	redis = mirror.example.com/library/redis
+//	postgres:16-alpine = mirror.example.com/postgres:16.4-alpine
+//
+// So an air-gapped CI points every example to its mirror with environment
+// variables alone.
+package images
+
+import (
+	"bufio"
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/testcontainers/testcontainers-go"
+)
+
+// Image is the reference of an image of the catalog, before overrides.
+type Image string
+
+// Images of the examples.
+const (
+	Postgres  Image = "postgres:16-alpine"
+	Redis     Image = "redis:7-alpine"
+	Alpine    Image = "alpine:latest"
+	Nginx     Image = "nginx:alpine"
+	Kafka     Image = "confluentinc/confluent-local:7.5.0"
+	Toxiproxy Image = "ghcr.io/shopify/toxiproxy:2.5.0"
+
+	// GoBuild and AppRuntime are the base images of testdata/app.
+	GoBuild    Image = "golang:1.24-alpine"
+	AppRuntime Image = "alpine:3.20"
+)
+
+// Versions the matrix tests run against.
+var (
+	PostgresVersions = []Image{
+		"postgres:13-alpine",
+		"postgres:14-alpine",
+		"postgres:15-alpine",
+		Postgres,
+		"postgres:17-alpine",
+	}
+
+	RedisVersions = []Image{
+		"redis:6-alpine",
+		Redis,
+		"valkey/valkey:8-alpine",
+	}
+)
+
+const (
+	// EnvPrefix starts the names of the variables overriding a repository.
+	EnvPrefix = "EXAMPLES_IMAGE_"
+
+	// EnvFile names the variable holding the path of the overrides file.
+	EnvFile = "EXAMPLES_IMAGES_FILE"
+
+	// envHubPrefix is the variable testcontainers reads the Docker Hub
+	// prefix from.
+	envHubPrefix = "TESTCONTAINERS_HUB_IMAGE_NAME_PREFIX"
+)
+
+var (
+	mu           sync.Mutex
+	loaded       bool
+	current      *config
+	substitutors []testcontainers.ImageSubstitutor
+)
+
+// config is what Load reads from the environment.
+type config struct {
+	// overrides are keyed by repository or by full reference.
+	overrides map[string]string
+	hubPrefix string
+}
+
+// Ref returns the reference to pull for the image. The configuration is
+// loaded on first use; Ref panics if it is invalid or a substitutor fails,
+// since no test could then run the image it asked for. Call Load first to
+// get the error instead.
+func (i Image) Ref() string {
+	ref, err := Resolve(string(i))
+	if err != nil {
+		panic(err)
+	}
+	return ref
+}
+
+// Pulled returns the reference Docker pulls for the image: Ref with the
+// Docker Hub prefix of testcontainers, if the image is still on Docker Hub.
+// It panics under the same conditions as Ref.
+func (i Image) Pulled() string {
+	ref, err := ResolvePulled(string(i))
+	if err != nil {
+		panic(err)
+	}
+	return ref
+}
+
+// Resolve returns the reference to pull for any image reference, catalog
+// or not, as Image.Ref does.
+func Resolve(ref string) (string, error) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	if !loaded {
+		if err := loadLocked(); err != nil {
+			return "", err
+		}
+	}
+
+	resolved := current.override(ref)
+
+	for _, s := range substitutors {
+		r, err := s.Substitute(resolved)
+		if err != nil {
+			return "", fmt.Errorf("substitute image %s with %s: %w", resolved, s.Description(), err)
+		}
+		resolved = r
+	}
+
+	return resolved, nil
+}
+
+// ResolvePulled returns the reference Docker pulls for any image reference,
+// catalog or not, as Image.Pulled does.
+func ResolvePulled(ref string) (string, error) {
+	resolved, err := Resolve(ref)
+	if err != nil {
+		return "", err
+	}
+
+	mu.Lock()
+	prefix := current.hubPrefix
+	mu.Unlock()
+
+	if prefix == "" || registry(resolved) != "" {
+		return resolved, nil
+	}
+	pulled, err := url.JoinPath(prefix, resolved)
+	if err != nil {
+		return "", fmt.Errorf("prepend %s to image %s: %w", prefix, resolved, err)
+	}
+	return pulled, nil
+}
+
+// Load reads the overrides and the Docker Hub prefix from the environment,
+// replacing those read before. Ref calls it on first use.
+func Load() error {
+	mu.Lock()
+	defer mu.Unlock()
+
+	return loadLocked()
+}
+
+func loadLocked() error {
+	cfg := &config{overrides: make(map[string]string)}
+
+	// The file is read first so that the variables win
+	if path := os.Getenv(EnvFile); path != "" {
+		if err := cfg.readFile(path); err != nil {
+			return err
+		}
+	}
+
+	for _, kv := range os.Environ() {
+		name, value, _ := strings.Cut(kv, "=")
+		key, ok := strings.CutPrefix(name, EnvPrefix)
+		if !ok || value == "" {
+			continue
+		}
+		cfg.overrides[strings.ToUpper(key)] = value
+	}
+
+	cfg.hubPrefix = os.Getenv(envHubPrefix)
+	if cfg.hubPrefix == "" {
+		cfg.hubPrefix = testcontainers.ReadConfig().Config.HubImageNamePrefix
+	}
+
+	current, loaded = cfg, true
+	return nil
+}
+
+// Use registers substitutors applied by Ref after the overrides, replacing
+// those registered before. It is meant for TestMain, or for the init
+// function of a file only built in CI.
+func Use(s ...testcontainers.ImageSubstitutor) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	substitutors = s
+}
+
+// readFile reads the overrides file at path.
+func (c *config) readFile(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("open images file: %w", err)
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		key, value, ok := strings.Cut(line, "=")
+		key, value = strings.TrimSpace(key), strings.TrimSpace(value)
+		if !ok || key == "" || value == "" {
+			return fmt.Errorf("%s:%d: want <image> = <replacement>, got %q", path, n, line)
+		}
+
+		// Full references are kept as is, repositories are keyed like
+		// the variables
+		if _, tag := split(key); tag == "" {
+			key = envKey(key)
+		}
+		c.overrides[key] = value
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("read images file: %w", err)
+	}
+
+	return nil
+}
+
+// override applies the override of ref, if any: first one for the full
+// reference, then one for its repository.
+func (c *config) override(ref string) string {
+	if r, ok := c.overrides[ref]; ok {
+		return r
+	}
+
+	repo, tag := split(ref)
+	r, ok := c.overrides[envKey(repo)]
+	if !ok {
+		return ref
+	}
+	if _, t := split(r); t != "" {
+		return r
+	}
+	return r + tag
+}
+
+// split splits ref into its repository and its tag or digest, including
+// the leading ':' or '@'. A ':' before the last '/' is a registry port.
+func split(ref string) (repo, tag string) {
+	if i := strings.IndexByte(ref, '@'); i >= 0 {
+		return ref[:i], ref[i:]
+	}
+	if i := strings.LastIndexByte(ref, ':'); i > strings.LastIndexByte(ref, '/') {
+		return ref[:i], ref[i:]
+	}
+	return ref, ""
+}
+
+// envKey returns the suffix of the variable overriding repo.
+func envKey(repo string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z':
+			return r - 'a' + 'A'
+		case r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+			return r
+		default:
+			return '_'
+		}
+	}, repo)
+}
+
+// registry returns the registry of ref, or "" for Docker Hub images without
+// an explicit registry. As for Docker, the first component of the name is a
+// registry if it has a '.' or a ':', or is localhost.
+func registry(ref string) string {
+	first, _, ok := strings.Cut(ref, "/")
+	if !ok {
+		return ""
+	}
+	if first == "localhost" || strings.ContainsAny(first, ".:") {
+		return first
+	}
+	return ""
+}
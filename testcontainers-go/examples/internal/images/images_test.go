@@ -0,0 +1,157 @@
+package images_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/testcontainers/testcontainers-go/examples/internal/images"
+)
+
+// setenv sets the variables and reloads the configuration, which is
+// reloaded again once they are restored.
+func setenv(t *testing.T, kv ...string) {
+	t.Helper()
+
+	t.Cleanup(func() {
+		require.NoError(t, images.Load())
+	})
+	t.Setenv("TESTCONTAINERS_HUB_IMAGE_NAME_PREFIX", "")
+	for i := 0; i < len(kv); i += 2 {
+		t.Setenv(kv[i], kv[i+1])
+	}
+	require.NoError(t, images.Load())
+}
+
+// writeFile writes an overrides file and returns its path.
+func writeFile(t *testing.T, lines ...string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "images.txt")
+	require.NoError(t, os.WriteFile(path, []byte(strings.Join(lines, "\n")), 0o644))
+	return path
+}
+
+func TestRefWithoutOverrides(t *testing.T) {
+	setenv(t)
+
+	require.Equal(t, "postgres:16-alpine", images.Postgres.Ref())
+	require.Equal(t, "ghcr.io/shopify/toxiproxy:2.5.0", images.Toxiproxy.Ref())
+}
+
+func TestRefEnvOverrides(t *testing.T) {
+	setenv(t,
+		"EXAMPLES_IMAGE_REDIS", "mirror.example.com/library/redis",
+		"EXAMPLES_IMAGE_CONFLUENTINC_CONFLUENT_LOCAL", "mirror.example.com/kafka:7.5.0-1",
+		"EXAMPLES_IMAGE_GHCR_IO_SHOPIFY_TOXIPROXY", "localhost:5000/toxiproxy",
+	)
+
+	// The tag of the catalog is kept unless the override has one
+	require.Equal(t, "mirror.example.com/library/redis:7-alpine", images.Redis.Ref())
+	require.Equal(t, "mirror.example.com/library/redis:6-alpine", images.RedisVersions[0].Ref())
+	require.Equal(t, "mirror.example.com/kafka:7.5.0-1", images.Kafka.Ref())
+	require.Equal(t, "localhost:5000/toxiproxy:2.5.0", images.Toxiproxy.Ref())
+
+	require.Equal(t, "valkey/valkey:8-alpine", images.RedisVersions[2].Ref())
+}
+
+func TestRefFileOverrides(t *testing.T) {
+	path := writeFile(t,
+		"# Mirrors",
+		"",
+		"postgres = mirror.example.com/library/postgres",
+		"postgres:17-alpine = mirror.example.com/postgres@sha256:0123",
+		"nginx = mirror.example.com/nginx",
+	)
+	setenv(t,
+		"EXAMPLES_IMAGES_FILE", path,
+		"EXAMPLES_IMAGE_NGINX", "other.example.com/nginx",
+	)
+
+	require.Equal(t, "mirror.example.com/library/postgres:16-alpine", images.Postgres.Ref())
+	require.Equal(t, "mirror.example.com/postgres@sha256:0123", images.Image("postgres:17-alpine").Ref())
+
+	// The variables win over the file
+	require.Equal(t, "other.example.com/nginx:alpine", images.Nginx.Ref())
+}
+
+func TestRefInvalidFile(t *testing.T) {
+	path := writeFile(t, "redis mirror.example.com/redis")
+	t.Cleanup(func() {
+		require.NoError(t, images.Load())
+	})
+	t.Setenv("EXAMPLES_IMAGES_FILE", path)
+
+	err := images.Load()
+	require.ErrorContains(t, err, "images.txt:1: want <image> = <replacement>")
+
+	t.Setenv("EXAMPLES_IMAGES_FILE", filepath.Join(t.TempDir(), "missing.txt"))
+	require.ErrorIs(t, images.Load(), os.ErrNotExist)
+}
+
+func TestRefHubPrefix(t *testing.T) {
+	setenv(t,
+		"EXAMPLES_IMAGE_NGINX", "mirror.example.com/nginx",
+		"TESTCONTAINERS_HUB_IMAGE_NAME_PREFIX", "hub.example.com/proxy",
+	)
+
+	// testcontainers prepends the prefix itself when it runs the image
+	require.Equal(t, "alpine:latest", images.Alpine.Ref())
+	require.Equal(t, "hub.example.com/proxy/alpine:latest", images.Alpine.Pulled())
+	require.Equal(t, "hub.example.com/proxy/valkey/valkey:8-alpine", images.RedisVersions[2].Pulled())
+
+	// Images not on Docker Hub, overridden or not, are left alone
+	require.Equal(t, "ghcr.io/shopify/toxiproxy:2.5.0", images.Toxiproxy.Pulled())
+	require.Equal(t, "mirror.example.com/nginx:alpine", images.Nginx.Pulled())
+
+	ref, err := images.ResolvePulled("docker.io/library/redis:7")
+	require.NoError(t, err)
+	require.Equal(t, "docker.io/library/redis:7", ref)
+}
+
+// TestRefHubPrefixWithoutHost checks that a prefix without a registry host,
+// which testcontainers would prepend again to an already prefixed image,
+// is left out of Ref.
+func TestRefHubPrefixWithoutHost(t *testing.T) {
+	setenv(t, "TESTCONTAINERS_HUB_IMAGE_NAME_PREFIX", "myorg")
+
+	require.Equal(t, "redis:7-alpine", images.Redis.Ref())
+	require.Equal(t, "myorg/redis:7-alpine", images.Redis.Pulled())
+}
+
+// suffix is an ImageSubstitutor appending a suffix to the tags.
+type suffix string
+
+func (s suffix) Description() string { return "suffix " + string(s) }
+
+func (s suffix) Substitute(image string) (string, error) {
+	if image == "" {
+		return "", errors.New("empty image")
+	}
+	return image + string(s), nil
+}
+
+func TestRefSubstitutors(t *testing.T) {
+	setenv(t,
+		"EXAMPLES_IMAGE_POSTGRES", "mirror.example.com/postgres",
+		"TESTCONTAINERS_HUB_IMAGE_NAME_PREFIX", "hub.example.com",
+	)
+	t.Cleanup(func() { images.Use() })
+
+	images.Use(suffix("-amd64"))
+
+	// Substitutors run after the overrides and before the hub prefix
+	require.Equal(t, "mirror.example.com/postgres:16-alpine-amd64", images.Postgres.Ref())
+	require.Equal(t, "redis:7-alpine-amd64", images.Redis.Ref())
+	require.Equal(t, "hub.example.com/redis:7-alpine-amd64", images.Redis.Pulled())
+
+	images.Use(suffix(""))
+
+	_, err := images.Resolve("")
+	require.ErrorContains(t, err, "substitute image  with suffix : empty image")
+	require.Panics(t, func() { images.Image("").Ref() })
+}
@@ -0,0 +1,72 @@
+package lifecycle
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/testcontainers/testcontainers-go"
+	tcexec "github.com/testcontainers/testcontainers-go/exec"
+)
+
+// Diagnostics returns hooks that, when t has failed, run each command in
+// the container before it stops and log its output with t.Log. Passing
+// tests run nothing.
+//
+// The commands run in PreStops rather than PreTerminates: Terminate stops
+// the container before calling the PreTerminates hooks, when nothing can be
+// executed in it anymore. Terminate, and so CleanupContainer, still runs
+// them, since it stops the container first.
+//
+// A failing command is logged and does not prevent the container from
+// stopping.
+func Diagnostics(t testing.TB, cmds ...[]string) testcontainers.ContainerLifecycleHooks {
+	return testcontainers.ContainerLifecycleHooks{
+		PreStops: []testcontainers.ContainerHook{
+			func(ctx context.Context, ctr testcontainers.Container) error {
+				if !t.Failed() {
+					return nil
+				}
+
+				for _, cmd := range cmds {
+					logCommand(ctx, t, ctr, cmd)
+				}
+				return nil
+			},
+		},
+	}
+}
+
+// PostgresDiagnostics returns Diagnostics listing the client connections
+// of pg_stat_activity with psql, connecting as POSTGRES_USER to POSTGRES_DB.
+func PostgresDiagnostics(t testing.TB) testcontainers.ContainerLifecycleHooks {
+	const query = `SELECT pid, usename, datname, state, wait_event_type, wait_event, now() - query_start AS running_for, query ` +
+		`FROM pg_stat_activity WHERE backend_type = 'client backend'`
+
+	return Diagnostics(t, []string{"sh", "-c", `psql -U "$POSTGRES_USER" -d "${POSTGRES_DB:-$POSTGRES_USER}" -c "` + query + `"`})
+}
+
+// RedisDiagnostics returns Diagnostics logging the output of INFO.
+func RedisDiagnostics(t testing.TB) testcontainers.ContainerLifecycleHooks {
+	return Diagnostics(t, []string{"redis-cli", "INFO"})
+}
+
+// logCommand runs cmd in ctr and logs its output.
+func logCommand(ctx context.Context, t testing.TB, ctr testcontainers.Container, cmd []string) {
+	name := strings.Join(cmd, " ")
+
+	exitCode, reader, err := ctr.Exec(ctx, cmd, tcexec.Multiplexed())
+	if err != nil {
+		t.Logf("diagnostics %s: %v", name, err)
+		return
+	}
+
+	output, err := io.ReadAll(reader)
+	if err != nil {
+		t.Logf("diagnostics %s: read output: %v", name, err)
+		return
+	}
+
+	t.Logf("diagnostics %s (exit code %d):\n%s", name, exitCode, output)
+}
@@ -0,0 +1,195 @@
+// Package lifecycle provides testcontainers.ContainerLifecycleHooks for
+// the chores that otherwise clutter every test starting a container:
+//
+//   - PostgresMigrations applies SQL migrations once the container is ready.
+//   - Diagnostics, PostgresDiagnostics and RedisDiagnostics log the state of
+//     the container when the test failed, before the container is stopped.
+//   - Timings records how long each phase of the container lifecycle took.
+//     Given as a customizer rather than as hooks, it also times the image
+//     pull.
+//
+// The hooks are passed with testcontainers.WithAdditionalLifecycleHooks, so
+// that the hooks of modules are kept:
+//
+//	timings := lifecycle.NewTimings()
+//
+//	pgContainer, err := postgres.Run(ctx, "postgres:16-alpine",
+//		postgres.BasicWaitStrategies(),
+//		testcontainers.WithAdditionalLifecycleHooks(
+//			timings.Hooks(),
+//			lifecycle.PostgresMigrations(migrations),
+//			lifecycle.PostgresDiagnostics(t),
+//		),
+//	)
+//	testcontainers.CleanupContainer(t, pgContainer)
+//	require.NoError(t, err)
+//
+// Hooks run in the order their ContainerLifecycleHooks are given.
+package lifecycle
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/testcontainers/testcontainers-go"
+)
+
+// Lifecycle phases recorded by Timings.
+const (
+	PhaseBuild     = "build"
+	PhasePull      = "pull"
+	PhaseCreate    = "create"
+	PhaseStart     = "start"
+	PhaseReady     = "ready"
+	PhaseStop      = "stop"
+	PhaseTerminate = "terminate"
+)
+
+// Phase is a completed lifecycle phase.
+type Phase struct {
+	Name     string
+	Start    time.Time
+	Duration time.Duration
+}
+
+// Timings records the duration of the lifecycle phases of a container:
+// building its image, creating it, starting it, waiting for it to be ready,
+// stopping it and removing it.
+//
+// A phase runs from the hook of Timings that opens it to the one that
+// closes it. Give Timings.Hooks first to time the other hooks of a phase
+// with it: the migrations of PostgresMigrations then count as part of ready.
+//
+// No hook runs before the image is pulled. Timings is also a
+// testcontainers.ContainerCustomizer that adds its hooks and an image
+// substitutor, which testcontainers runs just before it looks for the image
+// and pulls it if missing: the pull phase runs from there to the creation.
+type Timings struct {
+	mu      sync.Mutex
+	started map[string]time.Time
+	phases  []Phase
+}
+
+// NewTimings returns an empty Timings.
+func NewTimings() *Timings {
+	return &Timings{started: make(map[string]time.Time)}
+}
+
+// Hooks returns the hooks recording the phases.
+func (tm *Timings) Hooks() testcontainers.ContainerLifecycleHooks {
+	return testcontainers.ContainerLifecycleHooks{
+		PreBuilds:  []testcontainers.ContainerRequestHook{tm.beginRequestHook(PhaseBuild)},
+		PostBuilds: []testcontainers.ContainerRequestHook{tm.endRequestHook(PhaseBuild)},
+		PreCreates: []testcontainers.ContainerRequestHook{
+			tm.beginRequestHook(PhaseCreate),
+			tm.endRequestHook(PhasePull),
+		},
+		PostCreates:    []testcontainers.ContainerHook{tm.endHook(PhaseCreate)},
+		PreStarts:      []testcontainers.ContainerHook{tm.beginHook(PhaseStart)},
+		PostStarts:     []testcontainers.ContainerHook{tm.endHook(PhaseStart), tm.beginHook(PhaseReady)},
+		PostReadies:    []testcontainers.ContainerHook{tm.endHook(PhaseReady)},
+		PreStops:       []testcontainers.ContainerHook{tm.beginHook(PhaseStop)},
+		PostStops:      []testcontainers.ContainerHook{tm.endHook(PhaseStop)},
+		PreTerminates:  []testcontainers.ContainerHook{tm.beginHook(PhaseTerminate)},
+		PostTerminates: []testcontainers.ContainerHook{tm.endHook(PhaseTerminate)},
+	}
+}
+
+// Customize adds the hooks of Timings to the request, and the substitutor
+// opening the pull phase.
+func (tm *Timings) Customize(req *testcontainers.GenericContainerRequest) error {
+	req.ImageSubstitutors = append(req.ImageSubstitutors, pullTimer{tm})
+	return testcontainers.WithAdditionalLifecycleHooks(tm.Hooks()).Customize(req)
+}
+
+// pullTimer is an image substitutor leaving the image alone, run by
+// testcontainers just before the pull.
+type pullTimer struct {
+	tm *Timings
+}
+
+func (p pullTimer) Description() string { return "pull timer" }
+
+func (p pullTimer) Substitute(image string) (string, error) {
+	p.tm.begin(PhasePull)
+	return image, nil
+}
+
+func (tm *Timings) beginRequestHook(phase string) testcontainers.ContainerRequestHook {
+	return func(context.Context, testcontainers.ContainerRequest) error {
+		tm.begin(phase)
+		return nil
+	}
+}
+
+func (tm *Timings) endRequestHook(phase string) testcontainers.ContainerRequestHook {
+	return func(context.Context, testcontainers.ContainerRequest) error {
+		tm.end(phase)
+		return nil
+	}
+}
+
+func (tm *Timings) beginHook(phase string) testcontainers.ContainerHook {
+	return func(context.Context, testcontainers.Container) error {
+		tm.begin(phase)
+		return nil
+	}
+}
+
+func (tm *Timings) endHook(phase string) testcontainers.ContainerHook {
+	return func(context.Context, testcontainers.Container) error {
+		tm.end(phase)
+		return nil
+	}
+}
+
+func (tm *Timings) begin(phase string) {
+	tm.mu.Lock()
+	defer tm.mu.Unlock()
+
+	tm.started[phase] = time.Now()
+}
+
+func (tm *Timings) end(phase string) {
+	tm.mu.Lock()
+	defer tm.mu.Unlock()
+
+	start, ok := tm.started[phase]
+	if !ok {
+		return
+	}
+	delete(tm.started, phase)
+	tm.phases = append(tm.phases, Phase{Name: phase, Start: start, Duration: time.Since(start)})
+}
+
+// Phases returns the completed phases in the order they ended. A container
+// stopped and started again has the same phase more than once.
+func (tm *Timings) Phases() []Phase {
+	tm.mu.Lock()
+	defer tm.mu.Unlock()
+
+	return append([]Phase(nil), tm.phases...)
+}
+
+// Duration returns the total duration of the completed phases named name.
+func (tm *Timings) Duration(name string) time.Duration {
+	var total time.Duration
+	for _, p := range tm.Phases() {
+		if p.Name == name {
+			total += p.Duration
+		}
+	}
+	return total
+}
+
+// String formats the completed phases, one per line.
+func (tm *Timings) String() string {
+	var b strings.Builder
+	for _, p := range tm.Phases() {
+		fmt.Fprintf(&b, "%-10s %v\n", p.Name, p.Duration.Round(time.Millisecond))
+	}
+	return b.String()
+}
@@ -0,0 +1,179 @@
+package lifecycle_test
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/testcontainers/testcontainers-go"
+	"github.com/testcontainers/testcontainers-go/modules/postgres"
+
+	"github.com/testcontainers/testcontainers-go/examples/internal/fakecontainer"
+	"github.com/testcontainers/testcontainers-go/examples/internal/images"
+	"github.com/testcontainers/testcontainers-go/examples/internal/lifecycle"
+)
+
+// recorder is a testing.TB recording what the diagnostics log.
+type recorder struct {
+	testing.TB
+	failed bool
+	logs   []string
+}
+
+func (r *recorder) Failed() bool { return r.failed }
+func (r *recorder) Logf(format string, args ...any) {
+	r.logs = append(r.logs, fmt.Sprintf(format, args...))
+}
+
+func TestTimings(t *testing.T) {
+	ctx := context.Background()
+	timings := lifecycle.NewTimings()
+	hooks := timings.Hooks()
+
+	require.NoError(t, hooks.PreCreates[0](ctx, testcontainers.ContainerRequest{}))
+	time.Sleep(10 * time.Millisecond)
+	require.NoError(t, hooks.PostCreates[0](ctx, nil))
+
+	require.NoError(t, hooks.PreStarts[0](ctx, nil))
+	for _, hook := range hooks.PostStarts {
+		require.NoError(t, hook(ctx, nil))
+	}
+	time.Sleep(20 * time.Millisecond)
+	require.NoError(t, hooks.PostReadies[0](ctx, nil))
+
+	// A phase that never began is not recorded
+	require.NoError(t, hooks.PostStops[0](ctx, nil))
+
+	phases := timings.Phases()
+	require.Len(t, phases, 3)
+	require.Equal(t, lifecycle.PhaseCreate, phases[0].Name)
+	require.Equal(t, lifecycle.PhaseStart, phases[1].Name)
+	require.Equal(t, lifecycle.PhaseReady, phases[2].Name)
+
+	require.GreaterOrEqual(t, timings.Duration(lifecycle.PhaseCreate), 10*time.Millisecond)
+	require.GreaterOrEqual(t, timings.Duration(lifecycle.PhaseReady), 20*time.Millisecond)
+	require.Zero(t, timings.Duration(lifecycle.PhaseStop))
+	require.Regexp(t, `^create +\d+ms\nstart +\d+m?s\nready +\d+ms\n$`, timings.String())
+}
+
+func TestTimingsCustomize(t *testing.T) {
+	ctx := context.Background()
+	timings := lifecycle.NewTimings()
+
+	req := testcontainers.GenericContainerRequest{}
+	require.NoError(t, timings.Customize(&req))
+	require.Len(t, req.ImageSubstitutors, 1)
+	require.Len(t, req.LifecycleHooks, 1)
+
+	// testcontainers substitutes the image, pulls it, then creates the
+	// container
+	img, err := req.ImageSubstitutors[0].Substitute("redis:7-alpine")
+	require.NoError(t, err)
+	require.Equal(t, "redis:7-alpine", img)
+	time.Sleep(10 * time.Millisecond)
+
+	for _, hook := range req.LifecycleHooks[0].PreCreates {
+		require.NoError(t, hook(ctx, req.ContainerRequest))
+	}
+	require.NoError(t, req.LifecycleHooks[0].PostCreates[0](ctx, nil))
+
+	phases := timings.Phases()
+	require.Len(t, phases, 2)
+	require.Equal(t, lifecycle.PhasePull, phases[0].Name)
+	require.Equal(t, lifecycle.PhaseCreate, phases[1].Name)
+	require.GreaterOrEqual(t, timings.Duration(lifecycle.PhasePull), 10*time.Millisecond)
+}
+
+func TestDiagnostics(t *testing.T) {
+	ctx := context.Background()
+
+	ctr, err := fakecontainer.Run(ctx, "alpine:latest",
+		testcontainers.WithEnv(map[string]string{"STATE": "degraded"}),
+	)
+	testcontainers.CleanupContainer(t, ctr)
+	require.NoError(t, err)
+
+	for _, failed := range []bool{false, true} {
+		r := &recorder{failed: failed}
+		hooks := lifecycle.Diagnostics(r,
+			[]string{"sh", "-c", "echo state: $STATE"},
+			[]string{"missing-command"},
+		)
+
+		require.NoError(t, hooks.PreStops[0](ctx, ctr))
+
+		if !failed {
+			require.Empty(t, r.logs)
+			continue
+		}
+		require.Len(t, r.logs, 2)
+		require.Equal(t, "diagnostics sh -c echo state: $STATE (exit code 0):\nstate: degraded\n", r.logs[0])
+		require.True(t, strings.HasPrefix(r.logs[1], "diagnostics missing-command (exit code 126)"), r.logs[1])
+	}
+}
+
+func TestPostgresHooks(t *testing.T) {
+	ctx := context.Background()
+	timings := lifecycle.NewTimings()
+
+	pgContainer, err := postgres.Run(ctx, images.Postgres.Ref(),
+		postgres.WithDatabase("app"),
+		postgres.WithUsername("app"),
+		postgres.WithPassword("secret"),
+		postgres.BasicWaitStrategies(),
+		testcontainers.WithAdditionalLifecycleHooks(
+			timings.Hooks(),
+			lifecycle.PostgresMigrations(os.DirFS("testdata/migrations")),
+			lifecycle.PostgresDiagnostics(t),
+		),
+	)
+	testcontainers.CleanupContainer(t, pgContainer)
+	require.NoError(t, err)
+
+	connStr, err := pgContainer.ConnectionString(ctx, "sslmode=disable")
+	require.NoError(t, err)
+
+	db, err := sql.Open("postgres", connStr)
+	require.NoError(t, err)
+	defer db.Close()
+
+	// The migrations ran before Run returned
+	var name string
+	err = db.QueryRow(`SELECT name FROM events WHERE id = 1`).Scan(&name)
+	require.NoError(t, err)
+	require.Equal(t, "created", name)
+
+	require.NoError(t, pgContainer.Terminate(ctx))
+
+	var names []string
+	for _, p := range timings.Phases() {
+		names = append(names, p.Name)
+	}
+	require.Equal(t, []string{
+		lifecycle.PhaseCreate,
+		lifecycle.PhaseStart,
+		lifecycle.PhaseReady,
+		lifecycle.PhaseStop,
+		lifecycle.PhaseTerminate,
+	}, names)
+}
+
+func TestPostgresMigrationsFailure(t *testing.T) {
+	ctx := context.Background()
+
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "001_broken.sql"), []byte("CREATE TABLE;"), 0o600))
+
+	pgContainer, err := postgres.Run(ctx, images.Postgres.Ref(),
+		postgres.BasicWaitStrategies(),
+		testcontainers.WithAdditionalLifecycleHooks(lifecycle.PostgresMigrations(os.DirFS(dir))),
+	)
+	testcontainers.CleanupContainer(t, pgContainer)
+	require.ErrorContains(t, err, "apply migration 001_broken.sql")
+}
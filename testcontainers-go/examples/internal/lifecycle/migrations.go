@@ -0,0 +1,105 @@
+package lifecycle
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"io/fs"
+	"net"
+	"net/url"
+	"strings"
+
+	_ "github.com/lib/pq"
+	"github.com/testcontainers/testcontainers-go"
+)
+
+// PostgresMigrations returns hooks executing the .sql files at the root of
+// fsys, in lexical order, once the container is ready. A failing migration
+// makes Run fail.
+//
+// The hook receives the bare container rather than the module's, so the
+// connection settings are read from its POSTGRES_USER, POSTGRES_PASSWORD
+// and POSTGRES_DB environment, as set by postgres.Run.
+func PostgresMigrations(fsys fs.FS) testcontainers.ContainerLifecycleHooks {
+	return testcontainers.ContainerLifecycleHooks{
+		PostReadies: []testcontainers.ContainerHook{
+			func(ctx context.Context, ctr testcontainers.Container) error {
+				dsn, err := postgresDSN(ctx, ctr)
+				if err != nil {
+					return err
+				}
+
+				db, err := sql.Open("postgres", dsn)
+				if err != nil {
+					return fmt.Errorf("open database: %w", err)
+				}
+				defer db.Close()
+
+				return ApplyMigrations(ctx, db, fsys)
+			},
+		},
+	}
+}
+
+// ApplyMigrations executes the .sql files at the root of fsys in lexical
+// order, stopping at the first one that fails.
+func ApplyMigrations(ctx context.Context, db *sql.DB, fsys fs.FS) error {
+	// fs.Glob returns the names sorted.
+	names, err := fs.Glob(fsys, "*.sql")
+	if err != nil {
+		return fmt.Errorf("list migrations: %w", err)
+	}
+
+	for _, name := range names {
+		stmts, err := fs.ReadFile(fsys, name)
+		if err != nil {
+			return fmt.Errorf("read migration %s: %w", name, err)
+		}
+		if _, err := db.ExecContext(ctx, string(stmts)); err != nil {
+			return fmt.Errorf("apply migration %s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// postgresDSN builds the connection string of the database the container
+// was started with.
+func postgresDSN(ctx context.Context, ctr testcontainers.Container) (string, error) {
+	inspect, err := ctr.Inspect(ctx)
+	if err != nil {
+		return "", fmt.Errorf("inspect: %w", err)
+	}
+
+	env := make(map[string]string)
+	for _, kv := range inspect.Config.Env {
+		k, v, _ := strings.Cut(kv, "=")
+		env[k] = v
+	}
+
+	user := env["POSTGRES_USER"]
+	if user == "" {
+		user = "postgres"
+	}
+	database := env["POSTGRES_DB"]
+	if database == "" {
+		database = user
+	}
+
+	host, err := ctr.Host(ctx)
+	if err != nil {
+		return "", fmt.Errorf("host: %w", err)
+	}
+	port, err := ctr.MappedPort(ctx, "5432/tcp")
+	if err != nil {
+		return "", fmt.Errorf("mapped port: %w", err)
+	}
+
+	dsn := url.URL{
+		Scheme:   "postgres",
+		User:     url.UserPassword(user, env["POSTGRES_PASSWORD"]),
+		Host:     net.JoinHostPort(host, port.Port()),
+		Path:     database,
+		RawQuery: "sslmode=disable",
+	}
+	return dsn.String(), nil
+}
@@ -0,0 +1 @@
+INSERT INTO events (name) VALUES ('created');
@@ -0,0 +1,213 @@
+// Package logcapture records the output of containers and shows it only
+// when a test fails.
+//
+// A Capture is a testcontainers.LogConsumer that keeps stdout and stderr
+// apart and timestamps every line as it arrives. Green runs stay quiet;
+// when the test fails, the captured lines are written with t.Log at the end
+// of the test, so CI output shows what the container was doing:
+//
+//	logs := logcapture.New(t, "redis")
+//
+//	ctr, err := tcredis.Run(ctx, "redis:7-alpine",
+//		testcontainers.WithLogConsumers(logs),
+//	)
+//	testcontainers.CleanupContainer(t, ctr)
+//	require.NoError(t, err)
+//
+//	logs.RequireLine(regexp.MustCompile(`Ready to accept connections`), 10*time.Second)
+package logcapture
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/testcontainers/testcontainers-go"
+)
+
+// Stream names, as used in Line.Stream.
+const (
+	Stdout = "stdout"
+	Stderr = "stderr"
+)
+
+// timeFormat is the layout of the timestamps written on failure.
+const timeFormat = "15:04:05.000"
+
+// Compile-time check that Capture can be passed to WithLogConsumers.
+var _ testcontainers.LogConsumer = (*Capture)(nil)
+
+// Line is a line of container output.
+type Line struct {
+	// Time is when the line was received.
+	Time time.Time
+	// Stream is Stdout or Stderr.
+	Stream string
+	// Text is the line without its trailing newline.
+	Text string
+}
+
+// String formats the line as it is written on failure.
+func (l Line) String() string {
+	return fmt.Sprintf("%s %s | %s", l.Time.Format(timeFormat), l.Stream, l.Text)
+}
+
+// Capture records the lines of a container's output.
+type Capture struct {
+	t    testing.TB
+	name string
+
+	mu    sync.Mutex
+	lines []Line
+	// partial holds, per stream, the line whose newline has not arrived yet.
+	partial map[string]Line
+	// changed is closed and replaced whenever lines are added.
+	changed chan struct{}
+}
+
+// New returns a Capture whose lines are written to t, prefixed by name,
+// if t has failed when it ends.
+func New(t testing.TB, name string) *Capture {
+	c := &Capture{
+		t:       t,
+		name:    name,
+		partial: make(map[string]Line),
+		changed: make(chan struct{}),
+	}
+
+	t.Cleanup(func() {
+		if t.Failed() {
+			t.Logf("%s output:\n%s", c.name, c.String())
+		}
+	})
+
+	return c
+}
+
+// Accept implements testcontainers.LogConsumer. Content is split into
+// lines; a line without its newline yet is held until the rest arrives.
+func (c *Capture) Accept(l testcontainers.Log) {
+	stream := Stdout
+	if l.LogType == testcontainers.StderrLog {
+		stream = Stderr
+	}
+	now := time.Now()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	text := c.partial[stream].Text + string(l.Content)
+	for {
+		i := strings.IndexByte(text, '\n')
+		if i < 0 {
+			break
+		}
+		c.lines = append(c.lines, Line{Time: now, Stream: stream, Text: strings.TrimSuffix(text[:i], "\r")})
+		text = text[i+1:]
+	}
+	if text == "" {
+		delete(c.partial, stream)
+	} else {
+		c.partial[stream] = Line{Time: now, Stream: stream, Text: text}
+	}
+
+	close(c.changed)
+	c.changed = make(chan struct{})
+}
+
+// Lines returns the lines received so far, in order. The last line of a
+// stream is included even if its newline has not arrived, since the final
+// output of a crashing process often has none.
+func (c *Capture) Lines() []Line {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	lines := append([]Line(nil), c.lines...)
+	var pending []Line
+	for _, stream := range []string{Stdout, Stderr} {
+		if l, ok := c.partial[stream]; ok {
+			pending = append(pending, l)
+		}
+	}
+	slices.SortStableFunc(pending, func(a, b Line) int {
+		return a.Time.Compare(b.Time)
+	})
+	lines = append(lines, pending...)
+
+	return lines
+}
+
+// Stdout returns the lines written to stdout, newline-terminated.
+func (c *Capture) Stdout() string {
+	return c.text(Stdout)
+}
+
+// Stderr returns the lines written to stderr, newline-terminated.
+func (c *Capture) Stderr() string {
+	return c.text(Stderr)
+}
+
+func (c *Capture) text(stream string) string {
+	var b strings.Builder
+	for _, l := range c.Lines() {
+		if l.Stream == stream {
+			b.WriteString(l.Text)
+			b.WriteByte('\n')
+		}
+	}
+	return b.String()
+}
+
+// String returns all lines with their timestamps and streams.
+func (c *Capture) String() string {
+	var b bytes.Buffer
+	for _, l := range c.Lines() {
+		b.WriteString(l.String())
+		b.WriteByte('\n')
+	}
+	return b.String()
+}
+
+// WaitForLine returns the first line, received so far or within timeout,
+// that matches re.
+func (c *Capture) WaitForLine(re *regexp.Regexp, timeout time.Duration) (Line, error) {
+	deadline := time.NewTimer(timeout)
+	defer deadline.Stop()
+
+	next := 0
+	for {
+		c.mu.Lock()
+		lines, changed := c.lines[next:], c.changed
+		next = len(c.lines)
+		c.mu.Unlock()
+
+		for _, l := range lines {
+			if re.MatchString(l.Text) {
+				return l, nil
+			}
+		}
+
+		select {
+		case <-changed:
+		case <-deadline.C:
+			return Line{}, fmt.Errorf("no line of %s matched %q within %s", c.name, re, timeout)
+		}
+	}
+}
+
+// RequireLine is like WaitForLine but fails the test if no line matches.
+// It must be called from the goroutine running the test.
+func (c *Capture) RequireLine(re *regexp.Regexp, timeout time.Duration) Line {
+	c.t.Helper()
+
+	l, err := c.WaitForLine(re, timeout)
+	if err != nil {
+		c.t.Fatal(err)
+	}
+	return l
+}
@@ -0,0 +1,124 @@
+package logcapture_test
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/testcontainers/testcontainers-go"
+
+	"github.com/testcontainers/testcontainers-go/examples/internal/fakecontainer"
+	"github.com/testcontainers/testcontainers-go/examples/internal/logcapture"
+)
+
+// recorder is a testing.TB recording what a Capture does with it.
+type recorder struct {
+	testing.TB
+	failed   bool
+	cleanups []func()
+	logs     []string
+}
+
+func (r *recorder) Helper()          {}
+func (r *recorder) Failed() bool     { return r.failed }
+func (r *recorder) Cleanup(f func()) { r.cleanups = append(r.cleanups, f) }
+func (r *recorder) Logf(format string, args ...any) {
+	r.logs = append(r.logs, fmt.Sprintf(format, args...))
+}
+
+func (r *recorder) end() {
+	for i := len(r.cleanups) - 1; i >= 0; i-- {
+		r.cleanups[i]()
+	}
+}
+
+func TestAcceptSplitsLines(t *testing.T) {
+	logs := logcapture.New(t, "app")
+
+	logs.Accept(testcontainers.Log{LogType: testcontainers.StdoutLog, Content: []byte("first\nsec")})
+	logs.Accept(testcontainers.Log{LogType: testcontainers.StderrLog, Content: []byte("oops\r\n")})
+	logs.Accept(testcontainers.Log{LogType: testcontainers.StdoutLog, Content: []byte("ond\nthird")})
+
+	require.Equal(t, "first\nsecond\nthird\n", logs.Stdout())
+	require.Equal(t, "oops\n", logs.Stderr())
+
+	lines := logs.Lines()
+	require.Len(t, lines, 4)
+	require.Equal(t, logcapture.Stderr, lines[1].Stream)
+	require.False(t, lines[0].Time.IsZero())
+
+	// The rest of the pending line is joined to it, not added as a new line
+	logs.Accept(testcontainers.Log{LogType: testcontainers.StdoutLog, Content: []byte(" and last\n")})
+	require.Equal(t, "first\nsecond\nthird and last\n", logs.Stdout())
+}
+
+// TestOutputWithoutTrailingNewline checks that the last line of a crashing
+// container, which often has no newline, is written on failure.
+func TestOutputWithoutTrailingNewline(t *testing.T) {
+	r := &recorder{failed: true}
+	logs := logcapture.New(r, "app")
+	logs.Accept(testcontainers.Log{LogType: testcontainers.StdoutLog, Content: []byte("starting\n")})
+	logs.Accept(testcontainers.Log{LogType: testcontainers.StderrLog, Content: []byte("panic: out of memory")})
+	r.end()
+
+	require.Equal(t, "panic: out of memory\n", logs.Stderr())
+	require.Len(t, r.logs, 1)
+	require.Regexp(t, `stdout \| starting\n.* stderr \| panic: out of memory\n$`, r.logs[0])
+}
+
+func TestOutputOnlyOnFailure(t *testing.T) {
+	for _, failed := range []bool{false, true} {
+		r := &recorder{failed: failed}
+		logs := logcapture.New(r, "app")
+		logs.Accept(testcontainers.Log{LogType: testcontainers.StdoutLog, Content: []byte("hello\n")})
+		r.end()
+
+		if !failed {
+			require.Empty(t, r.logs)
+			continue
+		}
+		require.Len(t, r.logs, 1)
+		require.Regexp(t, `^app output:\n\d{2}:\d{2}:\d{2}\.\d{3} stdout \| hello\n$`, r.logs[0])
+	}
+}
+
+func TestWaitForLine(t *testing.T) {
+	logs := logcapture.New(t, "app")
+	logs.Accept(testcontainers.Log{LogType: testcontainers.StdoutLog, Content: []byte("starting\n")})
+
+	go func() {
+		time.Sleep(100 * time.Millisecond)
+		logs.Accept(testcontainers.Log{LogType: testcontainers.StdoutLog, Content: []byte("listening on :8080\n")})
+	}()
+
+	// Lines received before the call are matched too
+	line := logs.RequireLine(regexp.MustCompile(`^start`), time.Second)
+	require.Equal(t, "starting", line.Text)
+
+	line = logs.RequireLine(regexp.MustCompile(`listening on :(\d+)`), 5*time.Second)
+	require.Equal(t, "listening on :8080", line.Text)
+
+	_, err := logs.WaitForLine(regexp.MustCompile(`never`), 100*time.Millisecond)
+	require.ErrorContains(t, err, `no line of app matched "never" within 100ms`)
+}
+
+func TestCaptureContainer(t *testing.T) {
+	ctx := context.Background()
+	logs := logcapture.New(t, "alpine")
+
+	ctr, err := fakecontainer.Run(ctx, "alpine:latest",
+		testcontainers.WithCmd("sh", "-c", "echo 'Starting...'; echo 'warming up' >&2; echo 'Running...'; sleep 300"),
+		testcontainers.WithLogConsumers(logs),
+	)
+	testcontainers.CleanupContainer(t, ctr)
+	require.NoError(t, err)
+
+	// stdout and stderr are separate streams, so wait for the last line of each
+	logs.RequireLine(regexp.MustCompile(`^Running\.\.\.$`), 10*time.Second)
+	logs.RequireLine(regexp.MustCompile(`^warming up$`), 10*time.Second)
+	require.Equal(t, "Starting...\nRunning...\n", logs.Stdout())
+	require.Equal(t, "warming up\n", logs.Stderr())
+}
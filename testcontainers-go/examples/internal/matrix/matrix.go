@@ -0,0 +1,81 @@
+// Package matrix runs a test body against several images of a module, one
+// subtest per image.
+//
+// Testing every supported version of a dependency multiplies the number of
+// containers a test starts. The subtests of a Matrix run in parallel, but
+// no more than its parallelism at a time, so that a matrix of five images
+// does not start five containers at once on a small CI runner:
+//
+//	func TestBasicPostgres(t *testing.T) {
+//		matrix.Of(
+//			"postgres:13-alpine",
+//			"postgres:17-alpine",
+//		).Run(t, func(t *testing.T, image string) {
+//			pgContainer, err := postgres.Run(ctx, image, postgres.BasicWaitStrategies())
+//			testcontainers.CleanupContainer(t, pgContainer)
+//			require.NoError(t, err)
+//			// ...
+//		})
+//	}
+//
+// The subtests are named after the images, so a single version is selected
+// with -run:
+//
+//	go test -run 'TestBasicPostgres/postgres:17-alpine'
+package matrix
+
+import (
+	"testing"
+)
+
+// DefaultParallelism is how many subtests of a Matrix run at the same time
+// when WithParallelism is not called.
+const DefaultParallelism = 2
+
+// Matrix is a list of images to run a test body against. Images are
+// strings, or any string type such as images.Image.
+type Matrix[T ~string] struct {
+	images      []T
+	parallelism int
+}
+
+// Of returns a Matrix of the given images.
+func Of[T ~string](images ...T) *Matrix[T] {
+	return &Matrix[T]{images: images, parallelism: DefaultParallelism}
+}
+
+// WithParallelism sets how many subtests run at the same time. Values
+// below 1 run the subtests one after the other.
+func (m *Matrix[T]) WithParallelism(n int) *Matrix[T] {
+	m.parallelism = max(n, 1)
+	return m
+}
+
+// Images returns the images of the matrix.
+func (m *Matrix[T]) Images() []T {
+	return append([]T(nil), m.images...)
+}
+
+// Run runs body in a parallel subtest of t for each image. Run returns once
+// the subtests are started; t ends when they have completed, as for any
+// parallel subtest. The -parallel flag of go test still applies on top of
+// the parallelism of the matrix.
+func (m *Matrix[T]) Run(t *testing.T, body func(t *testing.T, image T)) {
+	t.Helper()
+
+	if len(m.images) == 0 {
+		t.Fatal("matrix: no images")
+	}
+
+	slots := make(chan struct{}, m.parallelism)
+	for _, image := range m.images {
+		t.Run(string(image), func(t *testing.T) {
+			t.Parallel()
+
+			slots <- struct{}{}
+			defer func() { <-slots }()
+
+			body(t, image)
+		})
+	}
+}
@@ -0,0 +1,82 @@
+package matrix_test
+
+import (
+	"flag"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/testcontainers/testcontainers-go/examples/internal/matrix"
+)
+
+func TestRunSubtests(t *testing.T) {
+	var (
+		mu   sync.Mutex
+		seen = map[string]string{}
+	)
+
+	t.Run("matrix", func(t *testing.T) {
+		matrix.Of("redis:6-alpine", "redis:7-alpine", "valkey/valkey:8-alpine").Run(t, func(t *testing.T, image string) {
+			mu.Lock()
+			defer mu.Unlock()
+			seen[t.Name()] = image
+		})
+	})
+
+	require.Equal(t, map[string]string{
+		"TestRunSubtests/matrix/redis:6-alpine":         "redis:6-alpine",
+		"TestRunSubtests/matrix/redis:7-alpine":         "redis:7-alpine",
+		"TestRunSubtests/matrix/valkey/valkey:8-alpine": "valkey/valkey:8-alpine",
+	}, seen)
+}
+
+func TestRunLimitsParallelism(t *testing.T) {
+	images := []string{"postgres:13", "postgres:14", "postgres:15", "postgres:16", "postgres:17"}
+
+	for _, parallelism := range []int{1, 2, 3} {
+		// -parallel caps the matrix too
+		limit := int32(min(parallelism, testParallel(t)))
+
+		var (
+			running, peak atomic.Int32
+			reached       = make(chan struct{})
+			once          sync.Once
+		)
+
+		t.Run("matrix", func(t *testing.T) {
+			matrix.Of(images...).WithParallelism(parallelism).Run(t, func(t *testing.T, image string) {
+				n := running.Add(1)
+				defer running.Add(-1)
+
+				for p := peak.Load(); n > p && !peak.CompareAndSwap(p, n); p = peak.Load() {
+				}
+				if n >= limit {
+					once.Do(func() { close(reached) })
+				}
+
+				// Hold the first subtests until limit of them run at once,
+				// which only happens if the matrix lets them
+				select {
+				case <-reached:
+				case <-time.After(5 * time.Second):
+					t.Errorf("%d subtests ran at the same time, want %d", peak.Load(), limit)
+				}
+			})
+		})
+
+		require.LessOrEqual(t, peak.Load(), limit)
+	}
+}
+
+// testParallel returns the value of the -parallel flag of go test.
+func testParallel(t *testing.T) int {
+	t.Helper()
+
+	n, err := strconv.Atoi(flag.Lookup("test.parallel").Value.String())
+	require.NoError(t, err)
+	return n
+}
@@ -0,0 +1,263 @@
+// Package pgfixture shares a single PostgreSQL container between the tests
+// of a package.
+//
+// The container is started once in TestMain, the schema migrations are
+// applied and the migrated database is snapshotted. Each test then gets its
+// own *sql.DB from Fixture.DB, and the database is restored to the snapshot
+// when the test ends, so tests see the same initial state without paying
+// for a fresh container each:
+//
+//	var fixture *pgfixture.Fixture
+//
+//	func TestMain(m *testing.M) {
+//		ctx := context.Background()
+//
+//		f, err := pgfixture.Start(ctx, pgfixture.WithMigrations(migrations))
+//		if err != nil {
+//			log.Fatalf("start postgres fixture: %v", err)
+//		}
+//		fixture = f
+//
+//		code := m.Run()
+//		if err := f.Terminate(ctx); err != nil {
+//			log.Printf("terminate postgres fixture: %v", err)
+//		}
+//		os.Exit(code)
+//	}
+//
+//	func TestCreateUser(t *testing.T) {
+//		db := fixture.DB(t)
+//		// ...
+//	}
+package pgfixture
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"io/fs"
+	"strings"
+	"sync"
+	"testing"
+
+	_ "github.com/lib/pq"
+	"github.com/testcontainers/testcontainers-go"
+	"github.com/testcontainers/testcontainers-go/modules/postgres"
+
+	"github.com/testcontainers/testcontainers-go/examples/internal/images"
+	"github.com/testcontainers/testcontainers-go/examples/internal/lifecycle"
+)
+
+const (
+	// DefaultImage is the catalog image started when WithImage is not
+	// given, after the overrides of the images package.
+	DefaultImage = images.Postgres
+
+	// DefaultDatabase is the database created when WithDatabase is not given.
+	// Snapshots cannot be taken of the default 'postgres' system database.
+	DefaultDatabase = "fixture"
+
+	snapshotName = "pgfixture_migrated"
+)
+
+// Fixture is a PostgreSQL container holding a migrated database that is
+// restored after every test.
+type Fixture struct {
+	ctr     *postgres.PostgresContainer
+	connStr string
+
+	// mu is held by a test from DB until its cleanup has restored the
+	// snapshot, so that tests sharing the database never overlap.
+	mu sync.Mutex
+
+	// ownerMu guards owner, the name of the test holding mu, and ownerDB,
+	// the pool returned to it.
+	ownerMu sync.Mutex
+	owner   string
+	ownerDB *sql.DB
+}
+
+// Option configures Start.
+type Option func(*options)
+
+type options struct {
+	image      string
+	database   string
+	migrations []fs.FS
+	customs    []testcontainers.ContainerCustomizer
+}
+
+// WithImage sets the PostgreSQL image, DefaultImage by default.
+func WithImage(img string) Option {
+	return func(o *options) {
+		o.image = img
+	}
+}
+
+// WithDatabase sets the name of the database, DefaultDatabase by default.
+func WithDatabase(name string) Option {
+	return func(o *options) {
+		o.database = name
+	}
+}
+
+// WithMigrations applies the .sql files at the root of fsys, in lexical
+// order, before the snapshot is taken. It can be given more than once.
+func WithMigrations(fsys fs.FS) Option {
+	return func(o *options) {
+		o.migrations = append(o.migrations, fsys)
+	}
+}
+
+// WithCustomizers passes additional options to postgres.Run.
+func WithCustomizers(customs ...testcontainers.ContainerCustomizer) Option {
+	return func(o *options) {
+		o.customs = append(o.customs, customs...)
+	}
+}
+
+// Start starts the container, applies the migrations and snapshots the
+// database. The container is terminated if any step fails.
+func Start(ctx context.Context, opts ...Option) (*Fixture, error) {
+	image, err := images.Resolve(string(DefaultImage))
+	if err != nil {
+		return nil, fmt.Errorf("resolve image: %w", err)
+	}
+
+	o := options{
+		image:    image,
+		database: DefaultDatabase,
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	customs := append([]testcontainers.ContainerCustomizer{
+		postgres.WithDatabase(o.database),
+		postgres.BasicWaitStrategies(),
+	}, o.customs...)
+
+	ctr, err := postgres.Run(ctx, o.image, customs...)
+	if err != nil {
+		return nil, terminate(ctr, fmt.Errorf("run postgres: %w", err))
+	}
+
+	f := &Fixture{ctr: ctr}
+	if err := f.init(ctx, o.migrations); err != nil {
+		return nil, terminate(ctr, err)
+	}
+
+	return f, nil
+}
+
+// init applies the migrations and takes the snapshot restored after tests.
+func (f *Fixture) init(ctx context.Context, migrations []fs.FS) error {
+	connStr, err := f.ctr.ConnectionString(ctx, "sslmode=disable")
+	if err != nil {
+		return fmt.Errorf("connection string: %w", err)
+	}
+	f.connStr = connStr
+
+	db, err := sql.Open("postgres", connStr)
+	if err != nil {
+		return fmt.Errorf("open database: %w", err)
+	}
+	for _, fsys := range migrations {
+		if err := lifecycle.ApplyMigrations(ctx, db, fsys); err != nil {
+			db.Close()
+			return err
+		}
+	}
+
+	// PostgreSQL can't snapshot a database with active connections.
+	if err := db.Close(); err != nil {
+		return fmt.Errorf("close database: %w", err)
+	}
+
+	if err := f.ctr.Snapshot(ctx, postgres.WithSnapshotName(snapshotName)); err != nil {
+		return fmt.Errorf("snapshot: %w", err)
+	}
+	return nil
+}
+
+// terminate terminates ctr, which may be nil, after a failed start.
+func terminate(ctr testcontainers.Container, err error) error {
+	if termErr := testcontainers.TerminateContainer(ctr); termErr != nil {
+		return fmt.Errorf("%w (terminate: %v)", err, termErr)
+	}
+	return err
+}
+
+// DB returns a connection pool to the migrated database for the test t.
+// When t ends, the pool is closed and the database restored to its migrated
+// state. Tests calling DB run one at a time, even when marked t.Parallel.
+//
+// A test owns the database until it ends: calling DB again from the same
+// test, or from one of its subtests, returns the same pool, and the
+// snapshot is only restored once the owning test ends.
+func (f *Fixture) DB(t testing.TB) *sql.DB {
+	t.Helper()
+
+	if db := f.ownedBy(t.Name()); db != nil {
+		return db
+	}
+
+	f.mu.Lock()
+
+	db, err := sql.Open("postgres", f.connStr)
+	if err != nil {
+		f.mu.Unlock()
+		t.Fatalf("open database: %v", err)
+	}
+	f.setOwner(t.Name(), db)
+
+	t.Cleanup(func() {
+		defer f.mu.Unlock()
+		f.setOwner("", nil)
+
+		if err := db.Close(); err != nil {
+			t.Errorf("close database: %v", err)
+		}
+		if err := f.ctr.Restore(context.Background(), postgres.WithSnapshotName(snapshotName)); err != nil {
+			t.Errorf("restore snapshot: %v", err)
+		}
+	})
+
+	return db
+}
+
+// ownedBy returns the pool of the test holding mu if it is the test named
+// name or one of its ancestors, or nil otherwise.
+func (f *Fixture) ownedBy(name string) *sql.DB {
+	f.ownerMu.Lock()
+	defer f.ownerMu.Unlock()
+
+	if f.owner == "" || (name != f.owner && !strings.HasPrefix(name, f.owner+"/")) {
+		return nil
+	}
+	return f.ownerDB
+}
+
+// setOwner records the test holding mu and its pool.
+func (f *Fixture) setOwner(name string, db *sql.DB) {
+	f.ownerMu.Lock()
+	defer f.ownerMu.Unlock()
+
+	f.owner = name
+	f.ownerDB = db
+}
+
+// ConnectionString returns the connection string of the migrated database.
+func (f *Fixture) ConnectionString() string {
+	return f.connStr
+}
+
+// Container returns the underlying PostgreSQL container.
+func (f *Fixture) Container() *postgres.PostgresContainer {
+	return f.ctr
+}
+
+// Terminate terminates the container.
+func (f *Fixture) Terminate(ctx context.Context) error {
+	return f.ctr.Terminate(ctx)
+}
@@ -0,0 +1,92 @@
+package pgfixture_test
+
+import (
+	"context"
+	"embed"
+	"io/fs"
+	"log"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/testcontainers/testcontainers-go/examples/internal/pgfixture"
+)
+
+//go:embed testdata/migrations/*.sql
+var migrationFiles embed.FS
+
+var fixture *pgfixture.Fixture
+
+func TestMain(m *testing.M) {
+	ctx := context.Background()
+
+	migrations, err := fs.Sub(migrationFiles, "testdata/migrations")
+	if err != nil {
+		log.Fatalf("migrations: %v", err)
+	}
+
+	f, err := pgfixture.Start(ctx, pgfixture.WithMigrations(migrations))
+	if err != nil {
+		log.Fatalf("start postgres fixture: %v", err)
+	}
+	fixture = f
+
+	code := m.Run()
+
+	if err := f.Terminate(ctx); err != nil {
+		log.Printf("terminate postgres fixture: %v", err)
+	}
+	os.Exit(code)
+}
+
+// TestFixtureMigrated checks that the migrations were applied in order.
+func TestFixtureMigrated(t *testing.T) {
+	db := fixture.DB(t)
+
+	var name string
+	err := db.QueryRow(`SELECT name FROM products WHERE id = 1`).Scan(&name)
+	require.NoError(t, err)
+	require.Equal(t, "Widget", name)
+}
+
+// TestFixtureRestored modifies the database in each subtest and checks
+// that the next one starts again from the migrated state.
+func TestFixtureRestored(t *testing.T) {
+	for _, product := range []string{"Gadget", "Gizmo"} {
+		t.Run(product, func(t *testing.T) {
+			db := fixture.DB(t)
+
+			var count int
+			err := db.QueryRow(`SELECT COUNT(*) FROM products`).Scan(&count)
+			require.NoError(t, err)
+			require.Equal(t, 1, count, "changes of the previous test should have been restored")
+
+			_, err = db.Exec(`INSERT INTO products (name, price) VALUES ($1, $2)`, product, 19.99)
+			require.NoError(t, err)
+
+			_, err = db.Exec(`DELETE FROM products WHERE name = 'Widget'`)
+			require.NoError(t, err)
+		})
+	}
+}
+
+// TestFixtureNested checks that a second DB call and a subtest get the pool
+// of the owning test instead of waiting for it to end.
+func TestFixtureNested(t *testing.T) {
+	db := fixture.DB(t)
+	require.Same(t, db, fixture.DB(t))
+
+	_, err := db.Exec(`INSERT INTO products (name, price) VALUES ('Gadget', 19.99)`)
+	require.NoError(t, err)
+
+	t.Run("subtest", func(t *testing.T) {
+		sub := fixture.DB(t)
+		require.Same(t, db, sub)
+
+		var count int
+		err := sub.QueryRow(`SELECT COUNT(*) FROM products`).Scan(&count)
+		require.NoError(t, err)
+		require.Equal(t, 2, count, "the subtest should see the changes of its parent")
+	})
+}
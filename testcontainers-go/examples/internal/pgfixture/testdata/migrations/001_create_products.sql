@@ -0,0 +1,5 @@
+CREATE TABLE products (
+	id SERIAL PRIMARY KEY,
+	name TEXT NOT NULL,
+	price DECIMAL(10, 2) NOT NULL
+);
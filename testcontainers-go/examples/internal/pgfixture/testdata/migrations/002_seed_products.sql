@@ -0,0 +1 @@
+INSERT INTO products (name, price) VALUES ('Widget', 9.99);
@@ -0,0 +1,133 @@
+// Package reuse keeps containers between test runs, for local development.
+//
+// Re-running a test while working on it starts its containers again every
+// time. With EXAMPLES_REUSE set, the containers started with Customize are
+// named after the test package and left running when the tests end, so that
+// the next run picks them up instead of starting new ones:
+//
+//	EXAMPLES_REUSE=1 TESTCONTAINERS_RYUK_DISABLED=true go test -run TestPostgresReuse
+//
+// Ryuk removes the containers of a run once it ends, reused or not, so it
+// must be disabled for the containers to survive the run. They are then
+// never removed by testcontainers: remove them with docker rm -f once done.
+//
+// A reused container holds whatever the previous run left in it. The tests
+// must reset it before using it, such as restoring a PostgreSQL snapshot or
+// flushing Redis.
+//
+// Reuse is meant for a developer re-running tests on their machine, never
+// for CI: two runs of the same package at the same time share the
+// containers.
+package reuse
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"regexp"
+	"runtime"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/testcontainers/testcontainers-go"
+)
+
+// EnvVar enables reuse when set to a non-empty value.
+const EnvVar = "EXAMPLES_REUSE"
+
+const envRyukDisabled = "TESTCONTAINERS_RYUK_DISABLED"
+
+// Enabled reports whether containers are reused.
+func Enabled() bool {
+	return os.Getenv(EnvVar) != ""
+}
+
+// Customize returns the option making the container reusable, or an option
+// doing nothing when reuse is disabled. key tells apart the containers of a
+// package, such as "postgres" and "redis".
+//
+// The container name is derived from the package calling Customize, key and
+// the image, command and environment of the container: changing them in the
+// test starts a new container rather than reusing a stale one. The option
+// must come after those changing them. Other changes, such as copied files,
+// need the container to be removed by hand.
+func Customize(tb testing.TB, key string) testcontainers.ContainerCustomizer {
+	tb.Helper()
+
+	if !Enabled() {
+		return testcontainers.CustomizeRequestOption(func(*testcontainers.GenericContainerRequest) error {
+			return nil
+		})
+	}
+	if os.Getenv(envRyukDisabled) != "true" {
+		tb.Logf("%s is set, but Ryuk removes the containers at the end of the run unless %s=true", EnvVar, envRyukDisabled)
+	}
+
+	pkg := callerPackage()
+	return testcontainers.CustomizeRequestOption(func(req *testcontainers.GenericContainerRequest) error {
+		return testcontainers.WithReuseByName(name(pkg, key, req.ContainerRequest))(req)
+	})
+}
+
+// Cleanup terminates ctr when tb ends, unless reuse is enabled: the
+// container is then left running for the next run.
+func Cleanup(tb testing.TB, ctr testcontainers.Container) {
+	tb.Helper()
+
+	if Enabled() {
+		tb.Log("Leaving the container running for the next run")
+		return
+	}
+	testcontainers.CleanupContainer(tb, ctr)
+}
+
+// invalidNameChars are the characters Docker rejects in container names
+var invalidNameChars = regexp.MustCompile(`[^a-zA-Z0-9_.-]+`)
+
+// name returns the container name, readable for docker ps: the last
+// element of the package path and key, followed by a hash telling apart
+// packages with the same name and changed configurations.
+func name(pkg, key string, req testcontainers.ContainerRequest) string {
+	h := sha256.New()
+	for _, s := range []string{pkg, key, req.Image} {
+		h.Write([]byte(s))
+		h.Write([]byte{0})
+	}
+	for _, arg := range req.Cmd {
+		h.Write([]byte(arg))
+		h.Write([]byte{0})
+	}
+	keys := make([]string, 0, len(req.Env))
+	for k := range req.Env {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	for _, k := range keys {
+		h.Write([]byte(k + "=" + req.Env[k]))
+		h.Write([]byte{0})
+	}
+
+	base := pkg[strings.LastIndex(pkg, "/")+1:]
+	readable := invalidNameChars.ReplaceAllString(base+"-"+key, "-")
+	return readable + "-" + hex.EncodeToString(h.Sum(nil))[:12]
+}
+
+// callerPackage returns the path of the package calling the function that
+// calls callerPackage.
+func callerPackage() string {
+	pc, _, _, ok := runtime.Caller(2)
+	if !ok {
+		return "unknown"
+	}
+	fn := runtime.FuncForPC(pc).Name()
+
+	// The function name is the package path followed by a dot and the
+	// function, method or closure: the path may contain dots, but not its
+	// last element
+	slash := strings.LastIndex(fn, "/")
+	if dot := strings.Index(fn[slash+1:], "."); dot >= 0 {
+		return fn[:slash+1+dot]
+	}
+	return fn
+}
@@ -0,0 +1,62 @@
+package reuse_test
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/testcontainers/testcontainers-go"
+
+	"github.com/testcontainers/testcontainers-go/examples/internal/reuse"
+)
+
+// customize applies the option of reuse.Customize to a request for image
+// with env
+func customize(t *testing.T, key, image string, env map[string]string) testcontainers.GenericContainerRequest {
+	t.Helper()
+
+	req := testcontainers.GenericContainerRequest{
+		ContainerRequest: testcontainers.ContainerRequest{Image: image, Env: env},
+	}
+	require.NoError(t, reuse.Customize(t, key).Customize(&req))
+	return req
+}
+
+func TestCustomizeDisabled(t *testing.T) {
+	t.Setenv(reuse.EnvVar, "")
+
+	require.False(t, reuse.Enabled())
+	req := customize(t, "postgres", "postgres:16-alpine", nil)
+	require.False(t, req.Reuse)
+	require.Empty(t, req.Name)
+}
+
+func TestCustomize(t *testing.T) {
+	t.Setenv(reuse.EnvVar, "1")
+	t.Setenv("TESTCONTAINERS_RYUK_DISABLED", "true")
+
+	require.True(t, reuse.Enabled())
+	req := customize(t, "postgres", "postgres:16-alpine", map[string]string{"A": "1", "B": "2"})
+	require.True(t, req.Reuse)
+	require.Regexp(t, regexp.MustCompile(`^reuse_test-postgres-[0-9a-f]{12}$`), req.Name)
+
+	t.Run("Deterministic", func(t *testing.T) {
+		again := customize(t, "postgres", "postgres:16-alpine", map[string]string{"B": "2", "A": "1"})
+		require.Equal(t, req.Name, again.Name)
+	})
+
+	t.Run("Changed", func(t *testing.T) {
+		for _, other := range []testcontainers.GenericContainerRequest{
+			customize(t, "redis", "postgres:16-alpine", map[string]string{"A": "1", "B": "2"}),
+			customize(t, "postgres", "postgres:17-alpine", map[string]string{"A": "1", "B": "2"}),
+			customize(t, "postgres", "postgres:16-alpine", map[string]string{"A": "1", "B": "3"}),
+		} {
+			require.NotEqual(t, req.Name, other.Name)
+		}
+	})
+
+	t.Run("InvalidCharacters", func(t *testing.T) {
+		other := customize(t, "cache/sessions v2", "redis:7-alpine", nil)
+		require.Regexp(t, regexp.MustCompile(`^reuse_test-cache-sessions-v2-[0-9a-f]{12}$`), other.Name)
+	})
+}
@@ -0,0 +1,138 @@
+// Package shared lets parallel tests share a container, each with
+// resources of its own inside it.
+//
+// A Container is started by the first test that needs it, once, whatever
+// the number of tests asking for it at the same time, and terminated by
+// TestMain with TerminateAll. Tests then isolate themselves inside the
+// container: a Redis database number taken from Slots, a PostgreSQL
+// schema, a Kafka topic...
+//
+//	var redisServer = shared.New(func(ctx context.Context, tb testing.TB) (*tcredis.RedisContainer, error) {
+//		return tcredis.Run(ctx, "redis:7-alpine")
+//	})
+//
+//	// Database 0 is left to tests that do not share
+//	var redisDBs = shared.NewSlots(1, 15)
+//
+//	func TestMain(m *testing.M) {
+//		code := m.Run()
+//		if err := shared.TerminateAll(context.Background()); err != nil {
+//			log.Printf("terminate shared containers: %v", err)
+//		}
+//		os.Exit(code)
+//	}
+//
+//	func TestCache(t *testing.T) {
+//		t.Parallel()
+//
+//		redisContainer := redisServer.Get(t)
+//		db := redisDBs.Acquire(t)
+//		// ...
+//	}
+package shared
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/testcontainers/testcontainers-go"
+)
+
+var (
+	mu         sync.Mutex
+	containers []terminator
+)
+
+type terminator interface {
+	terminate(ctx context.Context) error
+}
+
+// Container is a container shared by the tests of a package.
+type Container[T testcontainers.Container] struct {
+	start func(ctx context.Context, tb testing.TB) (T, error)
+
+	once sync.Once
+	ctr  T
+	err  error
+}
+
+// New returns a Container started by start on first use. start gets the
+// first test asking for the container, for logging or to record its
+// startup; the container must outlive it, so start must not register a
+// cleanup terminating it.
+func New[T testcontainers.Container](start func(ctx context.Context, tb testing.TB) (T, error)) *Container[T] {
+	c := &Container[T]{start: start}
+
+	mu.Lock()
+	containers = append(containers, c)
+	mu.Unlock()
+
+	return c
+}
+
+// Get returns the container, starting it if no test did yet. Tests calling
+// Get at the same time wait for the same start. If the container failed to
+// start, every test calling Get fails with the same error.
+func (c *Container[T]) Get(tb testing.TB) T {
+	tb.Helper()
+
+	c.once.Do(func() {
+		c.ctr, c.err = c.start(context.Background(), tb)
+	})
+	if c.err != nil {
+		tb.Fatalf("start shared container: %v", c.err)
+	}
+	return c.ctr
+}
+
+// terminate terminates the container if it was started, even partially.
+func (c *Container[T]) terminate(ctx context.Context) error {
+	// Waits for a start in progress, and prevents any later one
+	c.once.Do(func() {
+		c.err = errors.New("shared container already terminated")
+	})
+	return testcontainers.TerminateContainer(c.ctr, testcontainers.StopContext(ctx))
+}
+
+// TerminateAll terminates the containers started so far. It is meant for
+// TestMain, once the tests are done.
+func TerminateAll(ctx context.Context) error {
+	mu.Lock()
+	defer mu.Unlock()
+
+	var errs []error
+	for _, c := range containers {
+		if err := c.terminate(ctx); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Slots hands out numbers to tests, each to one test at a time, such as
+// the database numbers of a Redis server.
+type Slots struct {
+	free chan int
+}
+
+// NewSlots returns the slots first to last, included.
+func NewSlots(first, last int) *Slots {
+	s := &Slots{free: make(chan int, last-first+1)}
+	for n := first; n <= last; n++ {
+		s.free <- n
+	}
+	return s
+}
+
+// Acquire returns a free number, waiting for one if every number is taken.
+// The number is released when tb ends: cleanups registered after Acquire,
+// which run before, can reset what the number stands for.
+func (s *Slots) Acquire(tb testing.TB) int {
+	tb.Helper()
+
+	n := <-s.free
+	tb.Cleanup(func() { s.free <- n })
+	return n
+}
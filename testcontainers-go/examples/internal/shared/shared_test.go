@@ -0,0 +1,129 @@
+package shared_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/testcontainers/testcontainers-go"
+
+	"github.com/testcontainers/testcontainers-go/examples/internal/fakecontainer"
+	"github.com/testcontainers/testcontainers-go/examples/internal/shared"
+)
+
+// fatalRecorder is a testing.TB recording Fatalf instead of stopping.
+type fatalRecorder struct {
+	testing.TB
+	fatal string
+}
+
+func (r *fatalRecorder) Fatalf(format string, args ...any) {
+	r.fatal = fmt.Sprintf(format, args...)
+}
+
+func TestGetStartsOnce(t *testing.T) {
+	var starts atomic.Int32
+	ctr := shared.New(func(ctx context.Context, tb testing.TB) (*fakecontainer.Container, error) {
+		starts.Add(1)
+		return fakecontainer.Run(ctx, "alpine:latest", testcontainers.WithCmd("sleep", "300"))
+	})
+
+	var (
+		mu   sync.Mutex
+		seen = make(map[*fakecontainer.Container]bool)
+	)
+	t.Run("Tests", func(t *testing.T) {
+		for i := range 10 {
+			t.Run(fmt.Sprint(i), func(t *testing.T) {
+				t.Parallel()
+
+				c := ctr.Get(t)
+				mu.Lock()
+				seen[c] = true
+				mu.Unlock()
+			})
+		}
+	})
+
+	require.Equal(t, int32(1), starts.Load())
+	require.Len(t, seen, 1)
+
+	for c := range seen {
+		state, err := c.State(context.Background())
+		require.NoError(t, err)
+		require.True(t, state.Running, "the container outlives the tests")
+
+		require.NoError(t, shared.TerminateAll(context.Background()))
+
+		state, err = c.State(context.Background())
+		require.NoError(t, err)
+		require.False(t, state.Running)
+	}
+}
+
+func TestGetFails(t *testing.T) {
+	ctr := shared.New(func(context.Context, testing.TB) (*fakecontainer.Container, error) {
+		return nil, errors.New("no such image")
+	})
+
+	for range 2 {
+		rec := &fatalRecorder{TB: t}
+		ctr.Get(rec)
+		require.Equal(t, "start shared container: no such image", rec.fatal)
+	}
+}
+
+func TestGetAfterTerminateAll(t *testing.T) {
+	ctr := shared.New(func(context.Context, testing.TB) (*fakecontainer.Container, error) {
+		t.Error("a container is started after TerminateAll")
+		return nil, nil
+	})
+
+	require.NoError(t, shared.TerminateAll(context.Background()))
+
+	rec := &fatalRecorder{TB: t}
+	ctr.Get(rec)
+	require.Contains(t, rec.fatal, "already terminated")
+}
+
+func TestSlots(t *testing.T) {
+	slots := shared.NewSlots(1, 3)
+
+	var (
+		mu    sync.Mutex
+		inUse = make(map[int]bool)
+		peak  int
+	)
+	t.Run("Tests", func(t *testing.T) {
+		for i := range 12 {
+			t.Run(fmt.Sprint(i), func(t *testing.T) {
+				t.Parallel()
+
+				n := slots.Acquire(t)
+				require.GreaterOrEqual(t, n, 1)
+				require.LessOrEqual(t, n, 3)
+
+				mu.Lock()
+				taken := inUse[n]
+				inUse[n] = true
+				peak = max(peak, len(inUse))
+				mu.Unlock()
+				require.False(t, taken, "slot %d is used by two tests", n)
+
+				// Registered after Acquire, so it runs before the release
+				t.Cleanup(func() {
+					mu.Lock()
+					delete(inUse, n)
+					mu.Unlock()
+				})
+			})
+		}
+	})
+
+	require.Empty(t, inUse)
+	require.LessOrEqual(t, peak, 3)
+}
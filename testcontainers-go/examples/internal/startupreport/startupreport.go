@@ -0,0 +1,252 @@
+// Package startupreport reports how long the containers of a test binary
+// took to start, to find the slow examples.
+//
+// A Report is created for the package, and every container started by a
+// test is given the customizer of Record. TestMain writes the report once
+// the tests are done:
+//
+//	var startup = startupreport.New()
+//
+//	func TestMain(m *testing.M) {
+//		code := m.Run()
+//		if err := startup.WriteFiles(os.Getenv(startupreport.EnvDir)); err != nil {
+//			log.Printf("write startup report: %v", err)
+//		}
+//		os.Exit(code)
+//	}
+//
+//	func TestRedis(t *testing.T) {
+//		redisContainer, err := tcredis.Run(ctx, images.Redis.Ref(), startup.Record(t))
+//		// ...
+//	}
+//
+// The phases come from lifecycle.Timings: building the image, pulling it,
+// creating the container, starting it and waiting for its wait strategies.
+// The report lists the containers slowest first, as JSON for tools and as
+// a Markdown table for people, such as the summary of a CI job.
+package startupreport
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/testcontainers/testcontainers-go"
+
+	"github.com/testcontainers/testcontainers-go/examples/internal/lifecycle"
+)
+
+const (
+	// EnvDir names the variable holding the directory the report is
+	// written to. WriteFiles does nothing when it is empty.
+	EnvDir = "EXAMPLES_STARTUP_REPORT"
+
+	// JSONFile and MarkdownFile are the names of the files WriteFiles
+	// writes.
+	JSONFile     = "startup.json"
+	MarkdownFile = "startup.md"
+)
+
+// Phases are the startup phases of the report, in order.
+var Phases = []string{
+	lifecycle.PhaseBuild,
+	lifecycle.PhasePull,
+	lifecycle.PhaseCreate,
+	lifecycle.PhaseStart,
+	lifecycle.PhaseReady,
+}
+
+// Report collects the startup timings of containers. It is safe for
+// concurrent use by parallel tests.
+type Report struct {
+	mu         sync.Mutex
+	containers []*tracked
+}
+
+type tracked struct {
+	test    string
+	image   string
+	timings *lifecycle.Timings
+}
+
+// Entry is the startup of a container in the report.
+type Entry struct {
+	Test  string `json:"test"`
+	Image string `json:"image"`
+
+	// PhasesMS holds the duration of each completed phase, in
+	// milliseconds. A container started again after a stop has the
+	// durations of both starts.
+	PhasesMS map[string]float64 `json:"phases_ms"`
+	TotalMS  float64            `json:"total_ms"`
+}
+
+// New returns an empty Report.
+func New() *Report {
+	return &Report{}
+}
+
+// Record returns the customizer recording the startup of a container of
+// tb. It is given once per container.
+func (r *Report) Record(tb testing.TB) testcontainers.ContainerCustomizer {
+	return testcontainers.CustomizeRequestOption(func(req *testcontainers.GenericContainerRequest) error {
+		image := req.Image
+		if image == "" && req.FromDockerfile.Context != "" {
+			image = "build:" + req.FromDockerfile.Context
+		}
+
+		timings := lifecycle.NewTimings()
+
+		r.mu.Lock()
+		r.containers = append(r.containers, &tracked{test: tb.Name(), image: image, timings: timings})
+		r.mu.Unlock()
+
+		return timings.Customize(req)
+	})
+}
+
+// Entries returns the containers that completed at least one phase,
+// slowest first.
+func (r *Report) Entries() []Entry {
+	r.mu.Lock()
+	containers := append([]*tracked(nil), r.containers...)
+	r.mu.Unlock()
+
+	var entries []Entry
+	for _, c := range containers {
+		e := Entry{Test: c.test, Image: c.image, PhasesMS: make(map[string]float64)}
+		for _, p := range c.timings.Phases() {
+			if slices.Contains(Phases, p.Name) {
+				e.PhasesMS[p.Name] += ms(p.Duration)
+				e.TotalMS += ms(p.Duration)
+			}
+		}
+		if len(e.PhasesMS) > 0 {
+			entries = append(entries, e)
+		}
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].TotalMS > entries[j].TotalMS
+	})
+	return entries
+}
+
+// WriteJSON writes the entries as a JSON array.
+func (r *Report) WriteJSON(w io.Writer) error {
+	entries := r.Entries()
+	if entries == nil {
+		entries = []Entry{}
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(entries)
+}
+
+// WriteMarkdown writes the entries as a Markdown table, ending with a row
+// of totals.
+func (r *Report) WriteMarkdown(w io.Writer) error {
+	entries := r.Entries()
+
+	var b strings.Builder
+	b.WriteString("## Container startup\n\n")
+	if len(entries) == 0 {
+		b.WriteString("No container was started.\n")
+		_, err := io.WriteString(w, b.String())
+		return err
+	}
+
+	fmt.Fprintf(&b, "| Test | Image | %s | total |\n", strings.Join(Phases, " | "))
+	b.WriteString("|------|-------|" + strings.Repeat("---:|", len(Phases)+1) + "\n")
+
+	totals := make(map[string]float64)
+	var total float64
+	for _, e := range entries {
+		fmt.Fprintf(&b, "| %s | `%s` |", e.Test, e.Image)
+		for _, phase := range Phases {
+			v, ok := e.PhasesMS[phase]
+			fmt.Fprintf(&b, " %s |", cell(v, ok))
+			if ok {
+				totals[phase] += v
+			}
+		}
+		fmt.Fprintf(&b, " %s |\n", cell(e.TotalMS, true))
+		total += e.TotalMS
+	}
+
+	fmt.Fprintf(&b, "| **Total (%d)** | |", len(entries))
+	for _, phase := range Phases {
+		v, ok := totals[phase]
+		fmt.Fprintf(&b, " %s |", bold(cell(v, ok)))
+	}
+	fmt.Fprintf(&b, " %s |\n", bold(cell(total, true)))
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// WriteFiles writes JSONFile and MarkdownFile to dir, which is created if
+// needed. It does nothing if dir is empty.
+func (r *Report) WriteFiles(dir string) error {
+	if dir == "" {
+		return nil
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("create report directory: %w", err)
+	}
+
+	for name, write := range map[string]func(io.Writer) error{
+		JSONFile:     r.WriteJSON,
+		MarkdownFile: r.WriteMarkdown,
+	} {
+		if err := writeFile(filepath.Join(dir, name), write); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func writeFile(path string, write func(io.Writer) error) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("create %s: %w", path, err)
+	}
+	if err := write(f); err != nil {
+		f.Close()
+		return fmt.Errorf("write %s: %w", path, err)
+	}
+	return f.Close()
+}
+
+// ms returns d in milliseconds, rounded to a tenth.
+func ms(d time.Duration) float64 {
+	return float64(d.Round(100*time.Microsecond)) / float64(time.Millisecond)
+}
+
+// cell formats a duration in milliseconds, leaving missing phases empty.
+func cell(v float64, ok bool) string {
+	switch {
+	case !ok:
+		return ""
+	case v < 1000:
+		return fmt.Sprintf("%.0fms", v)
+	default:
+		return fmt.Sprintf("%.1fs", v/1000)
+	}
+}
+
+func bold(s string) string {
+	if s == "" {
+		return ""
+	}
+	return "**" + s + "**"
+}
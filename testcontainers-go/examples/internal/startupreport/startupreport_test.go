@@ -0,0 +1,119 @@
+package startupreport_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/testcontainers/testcontainers-go"
+
+	"github.com/testcontainers/testcontainers-go/examples/internal/lifecycle"
+	"github.com/testcontainers/testcontainers-go/examples/internal/startupreport"
+)
+
+// start runs the hooks the way testcontainers starts a container of image,
+// pulled in pull and ready after ready.
+func start(t *testing.T, report *startupreport.Report, image string, pull, ready time.Duration) {
+	t.Helper()
+	ctx := context.Background()
+
+	req := testcontainers.GenericContainerRequest{
+		ContainerRequest: testcontainers.ContainerRequest{Image: image},
+	}
+	require.NoError(t, report.Record(t).Customize(&req))
+	hooks := req.LifecycleHooks[0]
+
+	_, err := req.ImageSubstitutors[0].Substitute(image)
+	require.NoError(t, err)
+	time.Sleep(pull)
+
+	for _, hook := range hooks.PreCreates {
+		require.NoError(t, hook(ctx, req.ContainerRequest))
+	}
+	for _, hook := range hooks.PostCreates {
+		require.NoError(t, hook(ctx, nil))
+	}
+	for _, hook := range hooks.PreStarts {
+		require.NoError(t, hook(ctx, nil))
+	}
+	for _, hook := range hooks.PostStarts {
+		require.NoError(t, hook(ctx, nil))
+	}
+	time.Sleep(ready)
+	for _, hook := range hooks.PostReadies {
+		require.NoError(t, hook(ctx, nil))
+	}
+}
+
+func TestEntries(t *testing.T) {
+	report := startupreport.New()
+
+	start(t, report, "redis:7-alpine", 0, 10*time.Millisecond)
+	start(t, report, "postgres:16-alpine", 20*time.Millisecond, 30*time.Millisecond)
+
+	// A container that never got to pull its image is left out
+	require.NoError(t, report.Record(t).Customize(&testcontainers.GenericContainerRequest{}))
+
+	entries := report.Entries()
+	require.Len(t, entries, 2)
+
+	slowest := entries[0]
+	require.Equal(t, "TestEntries", slowest.Test)
+	require.Equal(t, "postgres:16-alpine", slowest.Image)
+	require.GreaterOrEqual(t, slowest.PhasesMS[lifecycle.PhasePull], 20.0)
+	require.GreaterOrEqual(t, slowest.PhasesMS[lifecycle.PhaseReady], 30.0)
+	require.NotContains(t, slowest.PhasesMS, lifecycle.PhaseBuild)
+
+	var sum float64
+	for _, ms := range slowest.PhasesMS {
+		sum += ms
+	}
+	require.InDelta(t, sum, slowest.TotalMS, 0.01)
+
+	require.Equal(t, "redis:7-alpine", entries[1].Image)
+	require.Less(t, entries[1].TotalMS, slowest.TotalMS)
+}
+
+func TestWrite(t *testing.T) {
+	report := startupreport.New()
+	start(t, report, "redis:7-alpine", 0, 10*time.Millisecond)
+
+	var buf bytes.Buffer
+	require.NoError(t, report.WriteMarkdown(&buf))
+	require.Regexp(t, "(?m)^\\| Test \\| Image \\| build \\| pull \\| create \\| start \\| ready \\| total \\|$", buf.String())
+	require.Regexp(t, "(?m)^\\| TestWrite \\| `redis:7-alpine` \\|  \\| \\d+ms \\| \\d+ms \\| \\d+ms \\| \\d+ms \\| \\d+ms \\|$", buf.String())
+	require.Contains(t, buf.String(), "| **Total (1)** |")
+
+	dir := filepath.Join(t.TempDir(), "report")
+	require.NoError(t, report.WriteFiles(dir))
+
+	data, err := os.ReadFile(filepath.Join(dir, startupreport.JSONFile))
+	require.NoError(t, err)
+	var entries []startupreport.Entry
+	require.NoError(t, json.Unmarshal(data, &entries))
+	require.Equal(t, report.Entries(), entries)
+
+	data, err = os.ReadFile(filepath.Join(dir, startupreport.MarkdownFile))
+	require.NoError(t, err)
+	require.Equal(t, buf.String(), string(data))
+}
+
+func TestWriteEmpty(t *testing.T) {
+	report := startupreport.New()
+
+	var buf bytes.Buffer
+	require.NoError(t, report.WriteJSON(&buf))
+	require.JSONEq(t, "[]", buf.String())
+
+	buf.Reset()
+	require.NoError(t, report.WriteMarkdown(&buf))
+	require.Contains(t, buf.String(), "No container was started.")
+
+	// Without a directory, nothing is written
+	require.NoError(t, report.WriteFiles(""))
+}
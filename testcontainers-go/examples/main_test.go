@@ -0,0 +1,43 @@
+package examples_test
+
+import (
+	"context"
+	"log"
+	"os"
+	"testing"
+
+	"github.com/testcontainers/testcontainers-go"
+
+	"github.com/testcontainers/testcontainers-go/examples/internal/shared"
+	"github.com/testcontainers/testcontainers-go/examples/internal/startupreport"
+)
+
+// startup records how long every container of the examples takes to start.
+// Set EXAMPLES_STARTUP_REPORT to a directory to get the report:
+//
+//	EXAMPLES_STARTUP_REPORT=/tmp/startup go test ./...
+var startup = startupreport.New()
+
+// recordStartup is given to every container a test starts, so that it
+// shows in the startup report
+func recordStartup(tb testing.TB) testcontainers.ContainerCustomizer {
+	return startup.Record(tb)
+}
+
+func TestMain(m *testing.M) {
+	code := m.Run()
+
+	// The shared containers outlive the tests using them
+	if err := shared.TerminateAll(context.Background()); err != nil {
+		log.Printf("terminate shared containers: %v", err)
+	}
+
+	if err := startup.WriteFiles(os.Getenv(startupreport.EnvDir)); err != nil {
+		log.Printf("write startup report: %v", err)
+		if code == 0 {
+			code = 1
+		}
+	}
+
+	os.Exit(code)
+}
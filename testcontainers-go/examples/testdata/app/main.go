@@ -0,0 +1,137 @@
+// Command app is the application container of the multi-container example.
+// It stores items in PostgreSQL and caches them in Redis.
+package main
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"os"
+	"strconv"
+	"time"
+
+	_ "github.com/lib/pq"
+	"github.com/redis/go-redis/v9"
+)
+
+type item struct {
+	ID     int64  `json:"id"`
+	Name   string `json:"name"`
+	Source string `json:"source,omitempty"`
+}
+
+type server struct {
+	db    *sql.DB
+	cache *redis.Client
+}
+
+func main() {
+	db, err := sql.Open("postgres", os.Getenv("DATABASE_URL"))
+	if err != nil {
+		log.Fatalf("open database: %v", err)
+	}
+	defer db.Close()
+
+	opt, err := redis.ParseURL(os.Getenv("REDIS_URL"))
+	if err != nil {
+		log.Fatalf("parse redis url: %v", err)
+	}
+	cache := redis.NewClient(opt)
+	defer cache.Close()
+
+	_, err = db.Exec(`CREATE TABLE IF NOT EXISTS items (id SERIAL PRIMARY KEY, name TEXT NOT NULL)`)
+	if err != nil {
+		log.Fatalf("create items table: %v", err)
+	}
+
+	s := &server{db: db, cache: cache}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /health", s.health)
+	mux.HandleFunc("POST /items", s.createItem)
+	mux.HandleFunc("GET /items/{id}", s.getItem)
+
+	log.Println("listening on :8080")
+	log.Fatal(http.ListenAndServe(":8080", mux))
+}
+
+// health reports whether both dependencies answer.
+func (s *server) health(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 2*time.Second)
+	defer cancel()
+
+	if err := s.db.PingContext(ctx); err != nil {
+		http.Error(w, "postgres: "+err.Error(), http.StatusServiceUnavailable)
+		return
+	}
+	if err := s.cache.Ping(ctx).Err(); err != nil {
+		http.Error(w, "redis: "+err.Error(), http.StatusServiceUnavailable)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]string{"postgres": "ok", "redis": "ok"})
+}
+
+// createItem inserts the item into PostgreSQL and counts it in Redis.
+func (s *server) createItem(w http.ResponseWriter, r *http.Request) {
+	var it item
+	if err := json.NewDecoder(r.Body).Decode(&it); err != nil || it.Name == "" {
+		http.Error(w, "invalid item", http.StatusBadRequest)
+		return
+	}
+
+	err := s.db.QueryRowContext(r.Context(), `INSERT INTO items (name) VALUES ($1) RETURNING id`, it.Name).Scan(&it.ID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	if err := s.cache.Incr(r.Context(), "items:created").Err(); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, it)
+}
+
+// getItem serves the item from Redis, loading it from PostgreSQL on a miss.
+func (s *server) getItem(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		http.Error(w, "invalid id", http.StatusBadRequest)
+		return
+	}
+
+	key := "item:" + strconv.FormatInt(id, 10)
+	it := item{ID: id, Source: "redis"}
+
+	it.Name, err = s.cache.Get(r.Context(), key).Result()
+	if errors.Is(err, redis.Nil) {
+		it.Source = "postgres"
+		err = s.db.QueryRowContext(r.Context(), `SELECT name FROM items WHERE id = $1`, id).Scan(&it.Name)
+		if errors.Is(err, sql.ErrNoRows) {
+			http.NotFound(w, r)
+			return
+		}
+		if err == nil {
+			err = s.cache.Set(r.Context(), key, it.Name, time.Minute).Err()
+		}
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, it)
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		log.Printf("encode response: %v", err)
+	}
+}
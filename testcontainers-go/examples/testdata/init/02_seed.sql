@@ -0,0 +1,8 @@
+INSERT INTO customers (name, email) VALUES
+    ('Alice', 'alice@example.com'),
+    ('Bob', 'bob@example.com');
+
+INSERT INTO orders (customer_id, total) VALUES
+    (1, 10.00),
+    (1, 25.50),
+    (2, 7.25);
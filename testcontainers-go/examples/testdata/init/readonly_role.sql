@@ -0,0 +1,4 @@
+-- Needs the view of reporting.sql, which sorts after this file: it must be
+-- run with WithOrderedInitScripts
+CREATE ROLE reporter LOGIN PASSWORD 'reporter';
+GRANT SELECT ON customer_totals TO reporter;
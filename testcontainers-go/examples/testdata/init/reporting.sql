@@ -0,0 +1,6 @@
+-- Needs the tables of 01_schema.sql
+CREATE VIEW customer_totals AS
+SELECT c.name, COUNT(o.id) AS orders, COALESCE(SUM(o.total), 0) AS total
+FROM customers c
+LEFT JOIN orders o ON o.customer_id = c.id
+GROUP BY c.name;
@@ -0,0 +1 @@
+ALTER TABLE accounts ADD COLUMN balance BIGINT NOT NULL DEFAULT 0;
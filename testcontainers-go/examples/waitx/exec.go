@@ -0,0 +1,69 @@
+package waitx
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	tcexec "github.com/testcontainers/testcontainers-go/exec"
+	"github.com/testcontainers/testcontainers-go/wait"
+)
+
+// Implement interface
+var (
+	_ wait.Strategy        = (*ExecStrategy)(nil)
+	_ wait.StrategyTimeout = (*ExecStrategy)(nil)
+)
+
+// ExecStrategy runs a command in the container until it exits with 0.
+//
+// Unlike wait.ForExec, which polls at a fixed interval and gives up on the
+// first Exec error, it backs off between attempts, fails as soon as the
+// container stops and reports the output of the last attempt.
+type ExecStrategy struct {
+	backoff
+	cmd []string
+}
+
+// ForExec returns a strategy running cmd in the container.
+func ForExec(cmd ...string) *ExecStrategy {
+	return &ExecStrategy{backoff: newBackoff(), cmd: cmd}
+}
+
+// WithStartupTimeout changes the default startup timeout.
+func (s *ExecStrategy) WithStartupTimeout(timeout time.Duration) *ExecStrategy {
+	s.timeout = &timeout
+	return s
+}
+
+// WithBackoff changes the initial and maximum delays between two attempts.
+func (s *ExecStrategy) WithBackoff(initial, maximum time.Duration) *ExecStrategy {
+	s.initial, s.max = initial, maximum
+	return s
+}
+
+// Timeout returns the startup timeout, if one was set.
+func (s *ExecStrategy) Timeout() *time.Duration {
+	return s.timeout
+}
+
+// WaitUntilReady implements wait.Strategy.
+func (s *ExecStrategy) WaitUntilReady(ctx context.Context, target wait.StrategyTarget) error {
+	return s.poll(ctx, target, func(ctx context.Context) error {
+		exitCode, reader, err := target.Exec(ctx, s.cmd, tcexec.Multiplexed())
+		if err != nil {
+			return fmt.Errorf("exec %q: %w", s.cmd, err)
+		}
+		if exitCode == 0 {
+			return nil
+		}
+
+		var output []byte
+		if reader != nil {
+			output, _ = io.ReadAll(reader)
+		}
+		return fmt.Errorf("exec %q: exit code %d: %s", s.cmd, exitCode, strings.TrimSpace(string(output)))
+	})
+}
@@ -0,0 +1,120 @@
+package waitx
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/docker/go-connections/nat"
+	_ "github.com/lib/pq"
+	"github.com/testcontainers/testcontainers-go/wait"
+)
+
+// DefaultMigrationsTable is the table created by golang-migrate, checked
+// when WithMigrationsTable is not given.
+const DefaultMigrationsTable = "schema_migrations"
+
+// Implement interface
+var (
+	_ wait.Strategy        = (*PostgresStrategy)(nil)
+	_ wait.StrategyTimeout = (*PostgresStrategy)(nil)
+)
+
+// PostgresStrategy waits until PostgreSQL accepts connections on a mapped
+// port and the migrations table exists.
+//
+// The official image starts a temporary server, only reachable through its
+// Unix socket, to run the scripts of /docker-entrypoint-initdb.d. Connecting
+// from the host therefore also waits for the init scripts to have run,
+// without counting the "ready to accept connections" log lines.
+type PostgresStrategy struct {
+	backoff
+	port     nat.Port
+	user     string
+	password string
+	database string
+	table    string
+}
+
+// ForPostgresMigrations returns a strategy connecting to the given port with
+// the defaults of the postgres module: user, password and database postgres.
+func ForPostgresMigrations(port nat.Port) *PostgresStrategy {
+	return &PostgresStrategy{
+		backoff:  newBackoff(),
+		port:     port,
+		user:     "postgres",
+		password: "postgres",
+		database: "postgres",
+		table:    DefaultMigrationsTable,
+	}
+}
+
+// WithCredentials changes the user, password and database to connect to.
+func (s *PostgresStrategy) WithCredentials(user, password, database string) *PostgresStrategy {
+	s.user, s.password, s.database = user, password, database
+	return s
+}
+
+// WithMigrationsTable changes the table that must exist, for example to
+// goose_db_version for goose. The name may be schema-qualified.
+func (s *PostgresStrategy) WithMigrationsTable(table string) *PostgresStrategy {
+	s.table = table
+	return s
+}
+
+// WithStartupTimeout changes the default startup timeout.
+func (s *PostgresStrategy) WithStartupTimeout(timeout time.Duration) *PostgresStrategy {
+	s.timeout = &timeout
+	return s
+}
+
+// WithBackoff changes the initial and maximum delays between two checks.
+func (s *PostgresStrategy) WithBackoff(initial, maximum time.Duration) *PostgresStrategy {
+	s.initial, s.max = initial, maximum
+	return s
+}
+
+// Timeout returns the startup timeout, if one was set.
+func (s *PostgresStrategy) Timeout() *time.Duration {
+	return s.timeout
+}
+
+// WaitUntilReady implements wait.Strategy.
+func (s *PostgresStrategy) WaitUntilReady(ctx context.Context, target wait.StrategyTarget) error {
+	return s.poll(ctx, target, func(ctx context.Context) error {
+		addr, err := endpoint(ctx, target, s.port)
+		if err != nil {
+			return err
+		}
+
+		dsn := url.URL{
+			Scheme:   "postgres",
+			User:     url.UserPassword(s.user, s.password),
+			Host:     addr,
+			Path:     s.database,
+			RawQuery: "sslmode=disable&connect_timeout=1",
+		}
+
+		db, err := sql.Open("postgres", dsn.String())
+		if err != nil {
+			return err
+		}
+		defer db.Close()
+
+		if err := db.PingContext(ctx); err != nil {
+			return fmt.Errorf("ping: %w", err)
+		}
+
+		var exists bool
+		err = db.QueryRowContext(ctx, `SELECT to_regclass($1) IS NOT NULL`, s.table).Scan(&exists)
+		if err != nil {
+			return fmt.Errorf("look up table %s: %w", s.table, err)
+		}
+		if !exists {
+			return fmt.Errorf("table %s does not exist", s.table)
+		}
+		return nil
+	})
+}
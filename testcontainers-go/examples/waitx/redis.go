@@ -0,0 +1,98 @@
+package waitx
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"time"
+
+	"github.com/docker/go-connections/nat"
+	"github.com/redis/go-redis/v9"
+	"github.com/testcontainers/testcontainers-go/wait"
+)
+
+// Implement interface
+var (
+	_ wait.Strategy        = (*RedisStrategy)(nil)
+	_ wait.StrategyTimeout = (*RedisStrategy)(nil)
+)
+
+// RedisStrategy waits until Redis answers PING on a mapped port. Redis
+// listens before it has loaded its dataset and answers LOADING meanwhile, so
+// a listening port alone does not mean that commands will succeed.
+type RedisStrategy struct {
+	backoff
+	port     nat.Port
+	password string
+}
+
+// ForRedisPing returns a strategy sending PING to the given port.
+func ForRedisPing(port nat.Port) *RedisStrategy {
+	return &RedisStrategy{backoff: newBackoff(), port: port}
+}
+
+// WithPassword authenticates before sending PING, for servers started with
+// requirepass.
+func (s *RedisStrategy) WithPassword(password string) *RedisStrategy {
+	s.password = password
+	return s
+}
+
+// WithStartupTimeout changes the default startup timeout.
+func (s *RedisStrategy) WithStartupTimeout(timeout time.Duration) *RedisStrategy {
+	s.timeout = &timeout
+	return s
+}
+
+// WithBackoff changes the initial and maximum delays between two PINGs.
+func (s *RedisStrategy) WithBackoff(initial, maximum time.Duration) *RedisStrategy {
+	s.initial, s.max = initial, maximum
+	return s
+}
+
+// Timeout returns the startup timeout, if one was set.
+func (s *RedisStrategy) Timeout() *time.Duration {
+	return s.timeout
+}
+
+// WaitUntilReady implements wait.Strategy.
+func (s *RedisStrategy) WaitUntilReady(ctx context.Context, target wait.StrategyTarget) error {
+	return s.poll(ctx, target, func(ctx context.Context) error {
+		addr, err := endpoint(ctx, target, s.port)
+		if err != nil {
+			return err
+		}
+
+		client := redis.NewClient(&redis.Options{
+			Addr:        addr,
+			Password:    s.password,
+			DialTimeout: time.Second,
+			MaxRetries:  -1,
+		})
+		defer client.Close()
+
+		pong, err := client.Ping(ctx).Result()
+		if err != nil {
+			return fmt.Errorf("ping: %w", err)
+		}
+		if pong != "PONG" {
+			return fmt.Errorf("ping: unexpected reply %q", pong)
+		}
+		return nil
+	})
+}
+
+// endpoint returns the host address of a container port.
+func endpoint(ctx context.Context, target wait.StrategyTarget, port nat.Port) (string, error) {
+	host, err := target.Host(ctx)
+	if err != nil {
+		return "", fmt.Errorf("host: %w", err)
+	}
+
+	mapped, err := target.MappedPort(ctx, port)
+	if err != nil {
+		return "", fmt.Errorf("mapped port: %w", err)
+	}
+
+	return net.JoinHostPort(host, mapped.Port()), nil
+}
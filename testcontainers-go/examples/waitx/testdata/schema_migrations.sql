@@ -0,0 +1,2 @@
+CREATE TABLE schema_migrations (version BIGINT PRIMARY KEY, dirty BOOLEAN NOT NULL);
+INSERT INTO schema_migrations (version, dirty) VALUES (1, false);
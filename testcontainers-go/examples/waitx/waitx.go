@@ -0,0 +1,90 @@
+// Package waitx provides wait strategies for the readiness checks that the
+// built-in strategies of the wait package cannot express:
+//
+//   - ForRedisPing waits until Redis answers PING, rather than only
+//     listening, which also covers Redis loading its dataset from disk.
+//   - ForPostgresMigrations waits until PostgreSQL accepts connections from
+//     the host and the migrations table exists.
+//   - ForExec runs a command in the container until it exits with 0,
+//     backing off between attempts.
+//
+// They implement wait.Strategy and are used like any other strategy:
+//
+//	ctr, err := tcredis.Run(ctx, "redis:7-alpine",
+//		testcontainers.WithWaitStrategy(waitx.ForRedisPing("6379/tcp")),
+//	)
+//
+// Every strategy polls with an exponential backoff, starting at
+// DefaultInitialInterval and doubling up to DefaultMaxInterval, and gives up
+// after DefaultStartupTimeout or as soon as the container stops running.
+package waitx
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/testcontainers/testcontainers-go/wait"
+)
+
+const (
+	// DefaultStartupTimeout is how long a strategy waits when
+	// WithStartupTimeout is not given.
+	DefaultStartupTimeout = 60 * time.Second
+
+	// DefaultInitialInterval is the delay after the first failed check.
+	DefaultInitialInterval = 100 * time.Millisecond
+
+	// DefaultMaxInterval caps the delay between two checks.
+	DefaultMaxInterval = 2 * time.Second
+)
+
+// backoff holds the polling settings shared by all strategies.
+type backoff struct {
+	timeout *time.Duration
+	initial time.Duration
+	max     time.Duration
+}
+
+func newBackoff() backoff {
+	return backoff{initial: DefaultInitialInterval, max: DefaultMaxInterval}
+}
+
+// poll calls check until it succeeds, the timeout expires or the container
+// stops running. The error of the last check is included when giving up.
+func (b backoff) poll(ctx context.Context, target wait.StrategyTarget, check func(context.Context) error) error {
+	timeout := DefaultStartupTimeout
+	if b.timeout != nil {
+		timeout = *b.timeout
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	delay := b.initial
+	for {
+		err := check(ctx)
+		if err == nil {
+			return nil
+		}
+		if ctx.Err() != nil {
+			return fmt.Errorf("%w: %w", ctx.Err(), err)
+		}
+
+		state, stateErr := target.State(ctx)
+		switch {
+		case stateErr != nil:
+			return fmt.Errorf("get state: %w", stateErr)
+		case !state.Running:
+			return fmt.Errorf("container is %s (exit code %d): %w", state.Status, state.ExitCode, err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("%w: %w", ctx.Err(), err)
+		case <-time.After(delay):
+		}
+
+		delay = min(2*delay, b.max)
+	}
+}
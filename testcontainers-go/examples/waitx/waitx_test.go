@@ -0,0 +1,147 @@
+package waitx_test
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/require"
+	"github.com/testcontainers/testcontainers-go"
+	"github.com/testcontainers/testcontainers-go/modules/postgres"
+	tcredis "github.com/testcontainers/testcontainers-go/modules/redis"
+
+	"github.com/testcontainers/testcontainers-go/examples/internal/fakecontainer"
+	"github.com/testcontainers/testcontainers-go/examples/internal/images"
+	"github.com/testcontainers/testcontainers-go/examples/waitx"
+)
+
+func TestForRedisPing(t *testing.T) {
+	ctx := context.Background()
+
+	redisContainer, err := tcredis.Run(ctx, images.Redis.Ref(),
+		testcontainers.WithWaitStrategy(waitx.ForRedisPing("6379/tcp")),
+	)
+	testcontainers.CleanupContainer(t, redisContainer)
+	require.NoError(t, err)
+
+	connStr, err := redisContainer.ConnectionString(ctx)
+	require.NoError(t, err)
+
+	opt, err := redis.ParseURL(connStr)
+	require.NoError(t, err)
+
+	client := redis.NewClient(opt)
+	defer client.Close()
+
+	require.NoError(t, client.Ping(ctx).Err())
+}
+
+func TestForRedisPingWithPassword(t *testing.T) {
+	ctx := context.Background()
+
+	redisContainer, err := testcontainers.Run(ctx, images.Redis.Ref(),
+		testcontainers.WithCmd("redis-server", "--requirepass", "secret"),
+		testcontainers.WithExposedPorts("6379/tcp"),
+		testcontainers.WithWaitStrategy(waitx.ForRedisPing("6379/tcp").WithPassword("secret")),
+	)
+	testcontainers.CleanupContainer(t, redisContainer)
+	require.NoError(t, err)
+
+	endpoint, err := redisContainer.PortEndpoint(ctx, "6379/tcp", "")
+	require.NoError(t, err)
+
+	client := redis.NewClient(&redis.Options{Addr: endpoint})
+	defer client.Close()
+
+	require.ErrorContains(t, client.Ping(ctx).Err(), "NOAUTH")
+}
+
+func TestForPostgresMigrations(t *testing.T) {
+	ctx := context.Background()
+
+	pgContainer, err := postgres.Run(ctx, images.Postgres.Ref(),
+		postgres.WithDatabase("app"),
+		postgres.WithInitScripts(filepath.Join("testdata", "schema_migrations.sql")),
+		testcontainers.WithWaitStrategy(
+			waitx.ForPostgresMigrations("5432/tcp").WithCredentials("postgres", "postgres", "app"),
+		),
+	)
+	testcontainers.CleanupContainer(t, pgContainer)
+	require.NoError(t, err)
+
+	connStr, err := pgContainer.ConnectionString(ctx, "sslmode=disable")
+	require.NoError(t, err)
+
+	db, err := sql.Open("postgres", connStr)
+	require.NoError(t, err)
+	defer db.Close()
+
+	var version int
+	err = db.QueryRow(`SELECT version FROM schema_migrations`).Scan(&version)
+	require.NoError(t, err)
+	require.Equal(t, 1, version)
+}
+
+func TestForPostgresMigrationsMissingTable(t *testing.T) {
+	ctx := context.Background()
+
+	pgContainer, err := postgres.Run(ctx, images.Postgres.Ref(),
+		testcontainers.WithWaitStrategy(
+			waitx.ForPostgresMigrations("5432/tcp").
+				WithMigrationsTable("goose_db_version").
+				WithStartupTimeout(10*time.Second),
+		),
+	)
+	testcontainers.CleanupContainer(t, pgContainer)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	require.ErrorContains(t, err, "table goose_db_version does not exist")
+}
+
+// The ForExec tests run against fakecontainer, so the strategy logic is
+// checked without Docker.
+
+func TestForExec(t *testing.T) {
+	ctx := context.Background()
+	ready := filepath.Join(t.TempDir(), "ready")
+
+	ctr, err := fakecontainer.Run(ctx, "alpine:latest",
+		testcontainers.WithEnv(map[string]string{"READY": ready}),
+		testcontainers.WithCmd("sh", "-c", `sleep 1; touch "$READY"; sleep 300`),
+		testcontainers.WithWaitStrategy(
+			waitx.ForExec("sh", "-c", `test -f "$READY"`).WithBackoff(50*time.Millisecond, 200*time.Millisecond),
+		),
+	)
+	testcontainers.CleanupContainer(t, ctr)
+	require.NoError(t, err)
+	require.FileExists(t, ready)
+}
+
+func TestForExecTimeout(t *testing.T) {
+	ctx := context.Background()
+
+	ctr, err := fakecontainer.Run(ctx, "alpine:latest",
+		testcontainers.WithCmd("sleep", "300"),
+		testcontainers.WithWaitStrategy(
+			waitx.ForExec("sh", "-c", "echo not yet; exit 1").WithStartupTimeout(time.Second),
+		),
+	)
+	testcontainers.CleanupContainer(t, ctr)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	require.ErrorContains(t, err, "exit code 1: not yet")
+}
+
+func TestForExecContainerExited(t *testing.T) {
+	ctx := context.Background()
+
+	start := time.Now()
+	ctr, err := fakecontainer.Run(ctx, "alpine:latest",
+		testcontainers.WithCmd("sh", "-c", "sleep 1; exit 3"),
+		testcontainers.WithWaitStrategy(waitx.ForExec("false")),
+	)
+	testcontainers.CleanupContainer(t, ctr)
+	require.ErrorContains(t, err, "container is exited (exit code 3)")
+	require.Less(t, time.Since(start), waitx.DefaultStartupTimeout, "the strategy should not wait for the timeout")
+}